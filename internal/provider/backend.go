@@ -0,0 +1,92 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/lupa95/passwork-client-go"
+)
+
+// Supported values of the api_version provider attribute.
+const (
+	apiVersionAuto = "auto"
+	apiVersionV4   = "v4"
+	apiVersionV7   = "v7"
+)
+
+// backend is implemented for every supported generation of the Passwork API. Requests and
+// responses use the data types of the Passwork v4 client, other generations translate them.
+type backend interface {
+	Login(ctx context.Context) error
+	Logout(ctx context.Context) error
+
+	GetVault(ctx context.Context, vaultId string) (passwork.VaultResponse, error)
+	AddVault(ctx context.Context, request passwork.VaultAddRequest) (passwork.VaultOperationResponse, error)
+	EditVault(ctx context.Context, vaultId string, request passwork.VaultEditRequest) (passwork.VaultOperationResponse, error)
+	DeleteVault(ctx context.Context, vaultId string) (passwork.DeleteResponse, error)
+
+	GetFolder(ctx context.Context, folderId string) (passwork.FolderResponse, error)
+	SearchFolder(ctx context.Context, request passwork.FolderSearchRequest) (passwork.FolderSearchResponse, error)
+	AddFolder(ctx context.Context, request passwork.FolderRequest) (passwork.FolderResponse, error)
+	EditFolder(ctx context.Context, folderId string, request passwork.FolderRequest) (passwork.FolderResponse, error)
+	DeleteFolder(ctx context.Context, folderId string) (passwork.DeleteResponse, error)
+	// ListFolders returns the folders directly inside the folder parentId, or in the root of the vault, if parentId is empty.
+	ListFolders(ctx context.Context, vaultId, parentId string) ([]passwork.FolderResponseData, error)
+
+	GetPassword(ctx context.Context, pwId string) (passwork.PasswordResponse, error)
+	SearchPassword(ctx context.Context, request passwork.PasswordSearchRequest) (passwork.PasswordSearchResponse, error)
+	AddPassword(ctx context.Context, request passwork.PasswordRequest) (passwork.PasswordResponse, error)
+	EditPassword(ctx context.Context, pwId string, request passwork.PasswordRequest) (passwork.PasswordResponse, error)
+	DeletePassword(ctx context.Context, pwId string) (passwork.DeleteResponse, error)
+	// ListPasswords returns the passwords directly inside the folder, or in the root of the vault, if folderId is empty.
+	ListPasswords(ctx context.Context, vaultId, folderId string) ([]passwork.PasswordResponseData, error)
+}
+
+// detectAPIVersion asks the server for its version. Passwork 7 reports it on the app version
+// endpoint of its API, older servers do not provide this endpoint and answer with 404. Any other
+// response, e.g. 401 from a proxy or an HTML error page, is an error, as the version is unknown.
+func detectAPIVersion(ctx context.Context, httpClient *http.Client, host string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, host+v7VersionPath, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return apiVersionV4, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected HTTP status %d from %s", resp.StatusCode, v7VersionPath)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	// Ensure the response comes from the API, not from a web frontend answering every path
+	var version struct {
+		Version string
+	}
+	if err := json.Unmarshal(body, &version); err != nil || version.Version == "" {
+		return "", fmt.Errorf("unexpected response from %s, which contains no Passwork version", v7VersionPath)
+	}
+
+	if strings.HasPrefix(version.Version, "7.") || version.Version == "7" {
+		return apiVersionV7, nil
+	}
+
+	return "", fmt.Errorf("unsupported Passwork version %s", version.Version)
+}
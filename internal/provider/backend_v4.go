@@ -0,0 +1,219 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/lupa95/passwork-client-go"
+)
+
+// v4Backend implements the backend for the Passwork v4 API with the Passwork client.
+// The client does not accept a context, so every call runs on a copy of the client,
+// whose requests are sent with the operation's context. Cancelling the operation, e.g.
+// with Ctrl-C, aborts its requests and retries.
+type v4Backend struct {
+	client *passwork.Client
+}
+
+var _ backend = &v4Backend{}
+
+func newV4Backend(client *passwork.Client) *v4Backend {
+	return &v4Backend{client: client}
+}
+
+// bind returns a copy of the client, which sends its requests with ctx.
+// The session token is kept by the sessionTransport, so it does not matter that a
+// login on the copy does not update the original client.
+func (b *v4Backend) bind(ctx context.Context) (*passwork.Client, *contextTransport) {
+	transport := &contextTransport{
+		base: b.client.HTTPClient.Transport,
+		ctx:  ctx,
+	}
+	client := *b.client
+	client.HTTPClient = &http.Client{
+		Timeout:   b.client.HTTPClient.Timeout,
+		Transport: transport,
+	}
+
+	return &client, transport
+}
+
+// callV4 calls the bound client and classifies errors with the status code of the last response.
+func callV4[T any](ctx context.Context, b *v4Backend, call func(*passwork.Client) (T, error)) (T, error) {
+	client, transport := b.bind(ctx)
+	result, err := call(client)
+	return result, v4Error(transport.statusCode, err)
+}
+
+// v4Error converts errors of the Passwork client and of get into apiErrors. Network errors,
+// e.g. timeouts, are returned unchanged.
+func v4Error(statusCode int, err error) error {
+	var urlErr *url.Error
+	if err == nil || errors.As(err, &urlErr) {
+		return err
+	}
+
+	return newAPIError(statusCode, err)
+}
+
+func (b *v4Backend) Login(ctx context.Context) error {
+	_, err := callV4(ctx, b, func(c *passwork.Client) (struct{}, error) { return struct{}{}, c.Login() })
+	return err
+}
+
+func (b *v4Backend) Logout(ctx context.Context) error {
+	_, err := callV4(ctx, b, func(c *passwork.Client) (struct{}, error) { return struct{}{}, c.Logout() })
+	return err
+}
+
+func (b *v4Backend) GetVault(ctx context.Context, vaultId string) (passwork.VaultResponse, error) {
+	return callV4(ctx, b, func(c *passwork.Client) (passwork.VaultResponse, error) { return c.GetVault(vaultId) })
+}
+
+func (b *v4Backend) AddVault(ctx context.Context, request passwork.VaultAddRequest) (passwork.VaultOperationResponse, error) {
+	return callV4(ctx, b, func(c *passwork.Client) (passwork.VaultOperationResponse, error) { return c.AddVault(request) })
+}
+
+func (b *v4Backend) EditVault(ctx context.Context, vaultId string, request passwork.VaultEditRequest) (passwork.VaultOperationResponse, error) {
+	return callV4(ctx, b, func(c *passwork.Client) (passwork.VaultOperationResponse, error) {
+		return c.EditVault(vaultId, request)
+	})
+}
+
+func (b *v4Backend) DeleteVault(ctx context.Context, vaultId string) (passwork.DeleteResponse, error) {
+	return callV4(ctx, b, func(c *passwork.Client) (passwork.DeleteResponse, error) { return c.DeleteVault(vaultId) })
+}
+
+func (b *v4Backend) GetFolder(ctx context.Context, folderId string) (passwork.FolderResponse, error) {
+	return callV4(ctx, b, func(c *passwork.Client) (passwork.FolderResponse, error) { return c.GetFolder(folderId) })
+}
+
+func (b *v4Backend) SearchFolder(ctx context.Context, request passwork.FolderSearchRequest) (passwork.FolderSearchResponse, error) {
+	return callV4(ctx, b, func(c *passwork.Client) (passwork.FolderSearchResponse, error) { return c.SearchFolder(request) })
+}
+
+func (b *v4Backend) AddFolder(ctx context.Context, request passwork.FolderRequest) (passwork.FolderResponse, error) {
+	return callV4(ctx, b, func(c *passwork.Client) (passwork.FolderResponse, error) { return c.AddFolder(request) })
+}
+
+func (b *v4Backend) EditFolder(ctx context.Context, folderId string, request passwork.FolderRequest) (passwork.FolderResponse, error) {
+	return callV4(ctx, b, func(c *passwork.Client) (passwork.FolderResponse, error) { return c.EditFolder(folderId, request) })
+}
+
+func (b *v4Backend) DeleteFolder(ctx context.Context, folderId string) (passwork.DeleteResponse, error) {
+	return callV4(ctx, b, func(c *passwork.Client) (passwork.DeleteResponse, error) { return c.DeleteFolder(folderId) })
+}
+
+func (b *v4Backend) GetPassword(ctx context.Context, passwordId string) (passwork.PasswordResponse, error) {
+	return callV4(ctx, b, func(c *passwork.Client) (passwork.PasswordResponse, error) { return c.GetPassword(passwordId) })
+}
+
+func (b *v4Backend) SearchPassword(ctx context.Context, request passwork.PasswordSearchRequest) (passwork.PasswordSearchResponse, error) {
+	return callV4(ctx, b, func(c *passwork.Client) (passwork.PasswordSearchResponse, error) { return c.SearchPassword(request) })
+}
+
+func (b *v4Backend) AddPassword(ctx context.Context, request passwork.PasswordRequest) (passwork.PasswordResponse, error) {
+	return callV4(ctx, b, func(c *passwork.Client) (passwork.PasswordResponse, error) { return c.AddPassword(request) })
+}
+
+func (b *v4Backend) EditPassword(ctx context.Context, passwordId string, request passwork.PasswordRequest) (passwork.PasswordResponse, error) {
+	return callV4(ctx, b, func(c *passwork.Client) (passwork.PasswordResponse, error) {
+		return c.EditPassword(passwordId, request)
+	})
+}
+
+func (b *v4Backend) DeletePassword(ctx context.Context, passwordId string) (passwork.DeleteResponse, error) {
+	return callV4(ctx, b, func(c *passwork.Client) (passwork.DeleteResponse, error) { return c.DeletePassword(passwordId) })
+}
+
+func (b *v4Backend) ListFolders(ctx context.Context, vaultId, parentId string) ([]passwork.FolderResponseData, error) {
+	path := "/vaults/" + url.PathEscape(vaultId) + "/folders"
+	if parentId != "" {
+		path = "/folders/" + url.PathEscape(parentId) + "/children"
+	}
+
+	var folders []passwork.FolderResponseData
+	err := b.get(ctx, path, &folders)
+	return folders, err
+}
+
+func (b *v4Backend) ListPasswords(ctx context.Context, vaultId, folderId string) ([]passwork.PasswordResponseData, error) {
+	path := "/vaults/" + url.PathEscape(vaultId) + "/passwords"
+	if folderId != "" {
+		path = "/folders/" + url.PathEscape(folderId) + "/passwords"
+	}
+
+	var passwords []passwork.PasswordResponseData
+	err := b.get(ctx, path, &passwords)
+	return passwords, err
+}
+
+// get sends a GET request to an endpoint, which is not implemented by the Passwork client,
+// and decodes the data of the response into result. Errors are returned like the client does.
+func (b *v4Backend) get(ctx context.Context, path string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.client.BaseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.client.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		Status string
+		Code   string
+		Data   json.RawMessage
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return v4Error(resp.StatusCode, fmt.Errorf("failed to parse JSON: %w", err))
+	}
+	if response.Status != "success" {
+		return v4Error(resp.StatusCode, errors.New(response.Code))
+	}
+
+	return json.Unmarshal(response.Data, result)
+}
+
+// contextTransport sends every request with ctx instead of the request's own context,
+// so the values, deadline and cancellation of the operation apply. The Passwork client
+// creates a context with a fixed timeout of 3 seconds for each request, which is replaced,
+// so the timeout of the provider and the resource operation applies instead.
+type contextTransport struct {
+	base http.RoundTripper
+	ctx  context.Context
+
+	// statusCode is the status code of the last response.
+	statusCode int
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(req.WithContext(t.ctx))
+	if resp != nil {
+		t.statusCode = resp.StatusCode
+	}
+
+	return resp, err
+}
@@ -0,0 +1,532 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/lupa95/passwork-client-go"
+)
+
+const (
+	v7APIPath     = "/api/v1"
+	v7VersionPath = v7APIPath + "/app/version"
+)
+
+// v7Backend implements the Passwork 7 API. Passwork 7 authenticates with an access token, which
+// can be renewed with a refresh token, and uses plain REST resources without a status envelope.
+type v7Backend struct {
+	baseURL    string
+	httpClient *http.Client
+
+	mutex        sync.RWMutex
+	accessToken  string
+	refreshToken string
+	// generation is increased with every token refresh, so concurrent requests failing
+	// with the same expired token only trigger a single refresh.
+	generation int
+}
+
+var _ backend = &v7Backend{}
+
+func newV7Backend(host, accessToken, refreshToken string, httpClient *http.Client) *v7Backend {
+	return &v7Backend{
+		baseURL:      host + v7APIPath,
+		httpClient:   httpClient,
+		accessToken:  accessToken,
+		refreshToken: refreshToken,
+	}
+}
+
+type v7Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type v7Tokens struct {
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+}
+
+type v7Vault struct {
+	Id        string `json:"id,omitempty"`
+	Name      string `json:"name"`
+	IsPrivate bool   `json:"isPrivate"`
+	Access    string `json:"access,omitempty"`
+}
+
+type v7Folder struct {
+	Id            string `json:"id,omitempty"`
+	Name          string `json:"name"`
+	VaultId       string `json:"vaultId,omitempty"`
+	ParentId      string `json:"parentId,omitempty"`
+	FoldersAmount int    `json:"foldersAmount,omitempty"`
+	ItemsAmount   int    `json:"itemsAmount,omitempty"`
+	Access        int    `json:"access,omitempty"`
+}
+
+type v7Item struct {
+	Id              string   `json:"id,omitempty"`
+	Name            string   `json:"name"`
+	VaultId         string   `json:"vaultId"`
+	FolderId        string   `json:"folderId,omitempty"`
+	Login           string   `json:"login,omitempty"`
+	CryptedPassword string   `json:"cryptedPassword,omitempty"`
+	Url             string   `json:"url,omitempty"`
+	Description     string   `json:"description,omitempty"`
+	Color           int      `json:"color,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+	Access          string   `json:"access,omitempty"`
+	AccessCode      int      `json:"accessCode,omitempty"`
+	UpdatedAt       string   `json:"updatedAt,omitempty"`
+}
+
+// v7FolderUpdate is the body of a PATCH request for a folder. The parent is always sent, so a
+// folder is moved to the top level of its vault with null.
+type v7FolderUpdate struct {
+	Name     string  `json:"name"`
+	ParentId *string `json:"parentId"`
+}
+
+// v7ItemUpdate is the body of a PATCH request for an item. All fields managed by the provider
+// are sent without omitempty, so cleared fields are sent as null or zero and not kept by the API.
+type v7ItemUpdate struct {
+	Name            string   `json:"name"`
+	VaultId         string   `json:"vaultId"`
+	FolderId        *string  `json:"folderId"`
+	Login           *string  `json:"login"`
+	CryptedPassword *string  `json:"cryptedPassword"`
+	Url             *string  `json:"url"`
+	Description     *string  `json:"description"`
+	Color           int      `json:"color"`
+	Tags            []string `json:"tags"`
+}
+
+// Login verifies the access token. Passwork 7 has no login, tokens are created in the user settings.
+func (b *v7Backend) Login(ctx context.Context) error {
+	return b.do(ctx, http.MethodGet, "/users/me", nil, nil)
+}
+
+// Logout does nothing. The access token is owned by the user and must not be revoked by the provider.
+func (b *v7Backend) Logout(ctx context.Context) error {
+	return nil
+}
+
+func (b *v7Backend) GetVault(ctx context.Context, vaultId string) (passwork.VaultResponse, error) {
+	var vault v7Vault
+	if err := b.do(ctx, http.MethodGet, "/vaults/"+url.PathEscape(vaultId), nil, &vault); err != nil {
+		return passwork.VaultResponse{}, err
+	}
+
+	scope := "domain"
+	if vault.IsPrivate {
+		scope = "user"
+	}
+
+	return passwork.VaultResponse{
+		Status: "success",
+		Data: passwork.VaultResponseData{
+			Id:     vault.Id,
+			Name:   vault.Name,
+			Access: vault.Access,
+			Scope:  scope,
+		},
+	}, nil
+}
+
+func (b *v7Backend) AddVault(ctx context.Context, request passwork.VaultAddRequest) (passwork.VaultOperationResponse, error) {
+	var vault v7Vault
+	if err := b.do(ctx, http.MethodPost, "/vaults", v7Vault{Name: request.Name, IsPrivate: request.IsPrivate}, &vault); err != nil {
+		return passwork.VaultOperationResponse{}, err
+	}
+
+	return passwork.VaultOperationResponse{Status: "success", Code: "vaultCreated", Data: vault.Id}, nil
+}
+
+func (b *v7Backend) EditVault(ctx context.Context, vaultId string, request passwork.VaultEditRequest) (passwork.VaultOperationResponse, error) {
+	if err := b.do(ctx, http.MethodPatch, "/vaults/"+url.PathEscape(vaultId), request, nil); err != nil {
+		return passwork.VaultOperationResponse{}, err
+	}
+
+	return passwork.VaultOperationResponse{Status: "success", Code: "vaultUpdated", Data: vaultId}, nil
+}
+
+func (b *v7Backend) DeleteVault(ctx context.Context, vaultId string) (passwork.DeleteResponse, error) {
+	if err := b.do(ctx, http.MethodDelete, "/vaults/"+url.PathEscape(vaultId), nil, nil); err != nil {
+		return passwork.DeleteResponse{}, err
+	}
+
+	return passwork.DeleteResponse{Status: "success", Data: "vaultDeleted"}, nil
+}
+
+func (b *v7Backend) GetFolder(ctx context.Context, folderId string) (passwork.FolderResponse, error) {
+	var folder v7Folder
+	if err := b.do(ctx, http.MethodGet, "/folders/"+url.PathEscape(folderId), nil, &folder); err != nil {
+		return passwork.FolderResponse{}, err
+	}
+
+	return passwork.FolderResponse{Status: "success", Data: folder.toV4()}, nil
+}
+
+func (b *v7Backend) SearchFolder(ctx context.Context, request passwork.FolderSearchRequest) (passwork.FolderSearchResponse, error) {
+	query := url.Values{"query": {request.Query}}
+	if request.VaultId != "" {
+		query.Set("vaultId", request.VaultId)
+	}
+
+	var folders []v7Folder
+	if err := b.do(ctx, http.MethodGet, "/folders/search?"+query.Encode(), nil, &folders); err != nil {
+		return passwork.FolderSearchResponse{}, err
+	}
+
+	response := passwork.FolderSearchResponse{Status: "success"}
+	for _, folder := range folders {
+		response.Data = append(response.Data, folder.toV4())
+	}
+
+	return response, nil
+}
+
+func (b *v7Backend) AddFolder(ctx context.Context, request passwork.FolderRequest) (passwork.FolderResponse, error) {
+	var folder v7Folder
+	body := v7Folder{Name: request.Name, VaultId: request.VaultId, ParentId: request.ParentId}
+	if err := b.do(ctx, http.MethodPost, "/folders", body, &folder); err != nil {
+		return passwork.FolderResponse{}, err
+	}
+
+	return passwork.FolderResponse{Status: "success", Code: "folderCreated", Data: folder.toV4()}, nil
+}
+
+func (b *v7Backend) EditFolder(ctx context.Context, folderId string, request passwork.FolderRequest) (passwork.FolderResponse, error) {
+	var folder v7Folder
+	body := v7FolderUpdate{Name: request.Name, ParentId: nullableString(request.ParentId)}
+	if err := b.do(ctx, http.MethodPatch, "/folders/"+url.PathEscape(folderId), body, &folder); err != nil {
+		return passwork.FolderResponse{}, err
+	}
+
+	return passwork.FolderResponse{Status: "success", Code: "folderRenamed", Data: folder.toV4()}, nil
+}
+
+func (b *v7Backend) DeleteFolder(ctx context.Context, folderId string) (passwork.DeleteResponse, error) {
+	if err := b.do(ctx, http.MethodDelete, "/folders/"+url.PathEscape(folderId), nil, nil); err != nil {
+		return passwork.DeleteResponse{}, err
+	}
+
+	return passwork.DeleteResponse{Status: "success", Data: "folderDeleted"}, nil
+}
+
+func (b *v7Backend) ListFolders(ctx context.Context, vaultId, parentId string) ([]passwork.FolderResponseData, error) {
+	query := url.Values{"vaultId": {vaultId}}
+	if parentId != "" {
+		query.Set("parentId", parentId)
+	}
+
+	var folders []v7Folder
+	if err := b.do(ctx, http.MethodGet, "/folders?"+query.Encode(), nil, &folders); err != nil {
+		return nil, err
+	}
+
+	var result []passwork.FolderResponseData
+	for _, folder := range folders {
+		result = append(result, folder.toV4())
+	}
+
+	return result, nil
+}
+
+func (b *v7Backend) GetPassword(ctx context.Context, pwId string) (passwork.PasswordResponse, error) {
+	var item v7Item
+	if err := b.do(ctx, http.MethodGet, "/items/"+url.PathEscape(pwId), nil, &item); err != nil {
+		return passwork.PasswordResponse{}, err
+	}
+
+	return passwork.PasswordResponse{Status: "success", Data: item.toV4()}, nil
+}
+
+func (b *v7Backend) SearchPassword(ctx context.Context, request passwork.PasswordSearchRequest) (passwork.PasswordSearchResponse, error) {
+	query := url.Values{"query": {request.Query}}
+	if request.VaultId != "" {
+		query.Set("vaultId", request.VaultId)
+	}
+	for _, tag := range request.Tags {
+		query.Add("tags", tag)
+	}
+
+	var items []v7Item
+	if err := b.do(ctx, http.MethodGet, "/items/search?"+query.Encode(), nil, &items); err != nil {
+		return passwork.PasswordSearchResponse{}, err
+	}
+
+	response := passwork.PasswordSearchResponse{Status: "success"}
+	for _, item := range items {
+		response.Data = append(response.Data, item.toV4())
+	}
+
+	return response, nil
+}
+
+func (b *v7Backend) AddPassword(ctx context.Context, request passwork.PasswordRequest) (passwork.PasswordResponse, error) {
+	var item v7Item
+	if err := b.do(ctx, http.MethodPost, "/items", v7ItemFromRequest(request), &item); err != nil {
+		return passwork.PasswordResponse{}, err
+	}
+
+	return passwork.PasswordResponse{Status: "success", Data: item.toV4()}, nil
+}
+
+func (b *v7Backend) EditPassword(ctx context.Context, pwId string, request passwork.PasswordRequest) (passwork.PasswordResponse, error) {
+	var item v7Item
+	if err := b.do(ctx, http.MethodPatch, "/items/"+url.PathEscape(pwId), v7ItemUpdateFromRequest(request), &item); err != nil {
+		return passwork.PasswordResponse{}, err
+	}
+
+	return passwork.PasswordResponse{Status: "success", Data: item.toV4()}, nil
+}
+
+func (b *v7Backend) DeletePassword(ctx context.Context, pwId string) (passwork.DeleteResponse, error) {
+	if err := b.do(ctx, http.MethodDelete, "/items/"+url.PathEscape(pwId), nil, nil); err != nil {
+		return passwork.DeleteResponse{}, err
+	}
+
+	return passwork.DeleteResponse{Status: "success", Data: "passwordDeleted"}, nil
+}
+
+func (b *v7Backend) ListPasswords(ctx context.Context, vaultId, folderId string) ([]passwork.PasswordResponseData, error) {
+	query := url.Values{"vaultId": {vaultId}}
+	if folderId != "" {
+		query.Set("folderId", folderId)
+	}
+
+	var items []v7Item
+	if err := b.do(ctx, http.MethodGet, "/items?"+query.Encode(), nil, &items); err != nil {
+		return nil, err
+	}
+
+	var result []passwork.PasswordResponseData
+	for _, item := range items {
+		result = append(result, item.toV4())
+	}
+
+	return result, nil
+}
+
+func (f v7Folder) toV4() passwork.FolderResponseData {
+	return passwork.FolderResponseData{
+		Id:              f.Id,
+		Name:            f.Name,
+		VaultId:         f.VaultId,
+		ParentId:        f.ParentId,
+		FoldersAmount:   f.FoldersAmount,
+		PasswordsAmount: f.ItemsAmount,
+		Access:          f.Access,
+	}
+}
+
+func (i v7Item) toV4() passwork.PasswordResponseData {
+	return passwork.PasswordResponseData{
+		Id:              i.Id,
+		Name:            i.Name,
+		VaultId:         i.VaultId,
+		FolderId:        i.FolderId,
+		Login:           i.Login,
+		CryptedPassword: i.CryptedPassword,
+		Url:             i.Url,
+		Description:     i.Description,
+		Color:           i.Color,
+		Tags:            i.Tags,
+		Access:          i.Access,
+		AccessCode:      i.AccessCode,
+		UpdatedAt:       i.UpdatedAt,
+	}
+}
+
+func v7ItemFromRequest(request passwork.PasswordRequest) v7Item {
+	return v7Item{
+		Name:            request.Name,
+		VaultId:         request.VaultId,
+		FolderId:        request.FolderId,
+		Login:           request.Login,
+		CryptedPassword: request.CryptedPassword,
+		Url:             request.Url,
+		Description:     request.Description,
+		Color:           request.Color,
+		Tags:            request.Tags,
+	}
+}
+
+func v7ItemUpdateFromRequest(request passwork.PasswordRequest) v7ItemUpdate {
+	tags := request.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return v7ItemUpdate{
+		Name:            request.Name,
+		VaultId:         request.VaultId,
+		FolderId:        nullableString(request.FolderId),
+		Login:           nullableString(request.Login),
+		CryptedPassword: nullableString(request.CryptedPassword),
+		Url:             nullableString(request.Url),
+		Description:     nullableString(request.Description),
+		Color:           request.Color,
+		Tags:            tags,
+	}
+}
+
+// nullableString returns nil for an empty string, which is sent as JSON null.
+func nullableString(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
+
+// do sends a request to the API and decodes the response into result. If the access token
+// expired, it is refreshed once and the request is replayed.
+func (b *v7Backend) do(ctx context.Context, method, path string, body, result interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+	}
+
+	b.mutex.RLock()
+	token, generation := b.accessToken, b.generation
+	b.mutex.RUnlock()
+
+	resp, err := b.send(ctx, method, path, payload, token)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		resp.Body.Close()
+		if err := b.refresh(ctx, generation); err != nil {
+			return err
+		}
+
+		b.mutex.RLock()
+		token = b.accessToken
+		b.mutex.RUnlock()
+
+		resp, err = b.send(ctx, method, path, payload, token)
+		if err != nil {
+			return err
+		}
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return v7ResponseError(resp.StatusCode, data)
+	}
+
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	return nil
+}
+
+func (b *v7Backend) send(ctx context.Context, method, path string, payload []byte, token string) (*http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, b.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	return b.httpClient.Do(req)
+}
+
+// refresh renews the access token, unless another request already refreshed it since generation.
+func (b *v7Backend) refresh(ctx context.Context, generation int) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.generation != generation {
+		return nil
+	}
+
+	if b.refreshToken == "" {
+		return newAPIError(http.StatusUnauthorized, errors.New("access token expired or was revoked and no refresh_token is configured"))
+	}
+
+	payload, err := json.Marshal(map[string]string{"refreshToken": b.refreshToken})
+	if err != nil {
+		return err
+	}
+
+	resp, err := b.send(ctx, http.MethodPost, "/sessions/refresh", payload, b.accessToken)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("refreshing the access token failed: %w", v7ResponseError(resp.StatusCode, data))
+	}
+
+	var tokens v7Tokens
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	b.accessToken = tokens.AccessToken
+	if tokens.RefreshToken != "" {
+		b.refreshToken = tokens.RefreshToken
+	}
+	b.generation++
+
+	return nil
+}
+
+// v7ResponseError converts an error response into the error codes used by the v4 API.
+func v7ResponseError(statusCode int, data []byte) error {
+	var response v7Error
+	if json.Unmarshal(data, &response) == nil && response.Code != "" {
+		return newAPIError(statusCode, errors.New(response.Code))
+	}
+
+	switch statusCode {
+	case http.StatusUnauthorized:
+		return newAPIError(statusCode, errors.New("unauthorized"))
+	case http.StatusForbidden:
+		return newAPIError(statusCode, errors.New("accessDenied"))
+	}
+
+	return newAPIError(statusCode, fmt.Errorf("unexpected response %d %s", statusCode, http.StatusText(statusCode)))
+}
@@ -0,0 +1,162 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lupa95/passwork-client-go"
+)
+
+func TestDetectAPIVersion(t *testing.T) {
+	testCases := map[string]struct {
+		handler     http.HandlerFunc
+		expected    string
+		expectError bool
+	}{
+		"v7": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"version":"7.0.4"}`)
+			},
+			expected: apiVersionV7,
+		},
+		"v4 without endpoint": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			expected: apiVersionV4,
+		},
+		"web frontend": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `<html></html>`)
+			},
+			expectError: true,
+		},
+		"unauthorized": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+			},
+			expectError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(testCase.handler)
+			defer server.Close()
+
+			version, err := detectAPIVersion(context.Background(), server.Client(), server.URL)
+			if testCase.expectError {
+				if err == nil {
+					t.Fatalf("expected error, got version %s", version)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if version != testCase.expected {
+				t.Fatalf("expected %s, got %s", testCase.expected, version)
+			}
+		})
+	}
+}
+
+func TestV7BackendPassword(t *testing.T) {
+	accessToken := "expired-token"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/sessions/refresh", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["refreshToken"] != "refresh-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		accessToken = "new-token"
+		fmt.Fprint(w, `{"accessToken":"new-token","refreshToken":"new-refresh-token"}`)
+	})
+	mux.HandleFunc("/api/v1/items", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+accessToken || accessToken != "new-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var item v7Item
+		if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		item.Id = "item"
+		item.Access = "write"
+		_ = json.NewEncoder(w).Encode(item)
+	})
+	mux.HandleFunc("/api/v1/items/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"code":"passwordNull","message":"Password not found"}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	backend := newV7Backend(server.URL, "expired-token", "refresh-token", &http.Client{Timeout: 5 * time.Second})
+
+	response, err := backend.AddPassword(context.Background(), passwork.PasswordRequest{
+		Name:            "test",
+		VaultId:         "vault",
+		CryptedPassword: "c2VjcmV0",
+		Tags:            []string{"tag"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if response.Data.Id != "item" || response.Data.VaultId != "vault" || response.Data.CryptedPassword != "c2VjcmV0" || response.Data.Access != "write" {
+		t.Fatalf("unexpected response: %+v", response.Data)
+	}
+	if backend.refreshToken != "new-refresh-token" {
+		t.Fatalf("expected refresh token to be rotated, got %q", backend.refreshToken)
+	}
+
+	_, err = backend.GetPassword(context.Background(), "missing")
+	if err == nil || err.Error() != "passwordNull" {
+		t.Fatalf("expected passwordNull error, got %v", err)
+	}
+}
+
+func TestV7BackendEditPassword(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{"id":"item","name":"test","vaultId":"vault"}`)
+	}))
+	defer server.Close()
+
+	backend := newV7Backend(server.URL, "token", "", &http.Client{Timeout: 5 * time.Second})
+
+	// Cleared fields must be sent, as a PATCH request keeps omitted fields
+	if _, err := backend.EditPassword(context.Background(), "item", passwork.PasswordRequest{Name: "test", VaultId: "vault"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, field := range []string{"folderId", "login", "cryptedPassword", "url", "description"} {
+		if value, ok := body[field]; !ok || value != nil {
+			t.Errorf("expected %s to be sent as null, got %v", field, body)
+		}
+	}
+	if body["color"] != float64(0) {
+		t.Errorf("expected color to be sent as 0, got %v", body)
+	}
+	if tags, ok := body["tags"].([]interface{}); !ok || len(tags) != 0 {
+		t.Errorf("expected tags to be sent as empty list, got %v", body)
+	}
+}
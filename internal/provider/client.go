@@ -0,0 +1,261 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sync"
+
+	"github.com/lupa95/passwork-client-go"
+)
+
+// passworkClient wraps the backend for the Passwork API version with the provider-level
+// settings, which are shared by all resources and data sources.
+type passworkClient struct {
+	backend
+
+	// clientSideEncryption is enabled, if the Passwork instance encrypts
+	// vaults and passwords on the client side with the user's master password.
+	clientSideEncryption bool
+	masterPassword       string
+
+	// retry is used to safely retry non-idempotent requests.
+	retry retryPolicy
+
+	// serializeVaultWrites runs creates, updates and deletes within the same vault one at a time.
+	serializeVaultWrites bool
+	vaultLocks           vaultLocks
+
+	// readOnly prevents planning any changes in Passwork.
+	readOnly bool
+
+	// vaultFilter limits the vaults, which may be accessed.
+	vaultFilter vaultFilter
+
+	// passwordPolicy is checked for planned passwords, if configured.
+	passwordPolicy *passwordPolicy
+
+	// vaultCache and folderCache cache the vaults and folders read to check
+	// the vault filter, permissions and the vaults of folders.
+	vaultCache  objectCache[passwork.VaultResponseData]
+	folderCache objectCache[passwork.FolderResponseData]
+
+	// vaultKeys caches the decrypted vault passwords by vault Id.
+	vaultKeys objectCache[string]
+}
+
+// objectCache caches objects read from the API by Id, as many resources check
+// the same vaults and folders during a plan.
+type objectCache[T any] struct {
+	mutex   sync.Mutex
+	entries map[string]*cacheEntry[T]
+}
+
+// cacheEntry is a cached object. done is closed, once the object was read.
+type cacheEntry[T any] struct {
+	done   chan struct{}
+	object T
+	err    error
+}
+
+// get returns the cached object or reads it, if it is not cached yet. Concurrent calls for
+// the same Id wait for a single read, while objects with other Ids are read in parallel.
+// Errors are not cached.
+func (c *objectCache[T]) get(id string, read func() (T, error)) (T, error) {
+	c.mutex.Lock()
+	if entry, ok := c.entries[id]; ok {
+		c.mutex.Unlock()
+
+		<-entry.done
+		// The read may have failed with the context of another operation, so it is read again
+		if entry.err != nil {
+			return c.get(id, read)
+		}
+		return entry.object, nil
+	}
+
+	if c.entries == nil {
+		c.entries = map[string]*cacheEntry[T]{}
+	}
+	entry := &cacheEntry[T]{done: make(chan struct{})}
+	c.entries[id] = entry
+	c.mutex.Unlock()
+
+	entry.object, entry.err = read()
+	if entry.err != nil {
+		c.mutex.Lock()
+		delete(c.entries, id)
+		c.mutex.Unlock()
+	}
+	close(entry.done)
+
+	return entry.object, entry.err
+}
+
+// set caches the object.
+func (c *objectCache[T]) set(id string, object T) {
+	done := make(chan struct{})
+	close(done)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.entries == nil {
+		c.entries = map[string]*cacheEntry[T]{}
+	}
+	c.entries[id] = &cacheEntry[T]{done: done, object: object}
+}
+
+func newPassworkClient(backend backend, clientSideEncryption bool, masterPassword string) *passworkClient {
+	return &passworkClient{
+		backend:              backend,
+		clientSideEncryption: clientSideEncryption,
+		masterPassword:       masterPassword,
+	}
+}
+
+// vaultPasswords reports whether the backend supports the master passwords of vaults.
+// The Passwork 7 API is not supported, as its vault endpoints do not take or return them.
+func (c *passworkClient) vaultPasswords() bool {
+	_, v7 := c.backend.(*v7Backend)
+	return !v7
+}
+
+// encryptVaultPassword encrypts the master password of a new vault.
+func (c *passworkClient) encryptVaultPassword(vaultPassword string) (string, error) {
+	if !c.clientSideEncryption {
+		return base64.StdEncoding.EncodeToString([]byte(vaultPassword)), nil
+	}
+
+	return encryptString(vaultPassword, c.masterPassword)
+}
+
+// decryptVaultPassword decrypts the master password of a vault returned by the API.
+func (c *passworkClient) decryptVaultPassword(vaultPasswordCrypted string) (string, error) {
+	if !c.clientSideEncryption {
+		vaultPassword, err := base64.StdEncoding.DecodeString(vaultPasswordCrypted)
+		return string(vaultPassword), err
+	}
+
+	return decryptString(vaultPasswordCrypted, c.masterPassword)
+}
+
+// vaultKey returns the decrypted password of the vault, which is used as key for its entries.
+func (c *passworkClient) vaultKey(ctx context.Context, vaultId string) (string, error) {
+	return c.vaultKeys.get(vaultId, func() (string, error) {
+		response, err := c.GetVault(ctx, vaultId)
+		if err != nil {
+			return "", err
+		}
+
+		key, err := c.decryptVaultPassword(response.Data.VaultPasswordCrypted)
+		if err != nil {
+			return "", fmt.Errorf("could not decrypt key of vault %s: %w", vaultId, err)
+		}
+
+		return key, nil
+	})
+}
+
+// passwordKey returns the key used for encrypting a password entry. Entries either have
+// their own key, encrypted with the vault key, or are encrypted with the vault key directly.
+func (c *passworkClient) passwordKey(ctx context.Context, vaultId, cryptedKey string) (string, error) {
+	key, err := c.vaultKey(ctx, vaultId)
+	if err != nil {
+		return "", err
+	}
+
+	if cryptedKey == "" {
+		return key, nil
+	}
+
+	return decryptString(cryptedKey, key)
+}
+
+// encryptPassword encrypts the value of a password entry stored in the given vault. Entries
+// with their own key are encrypted with the key, which is decrypted from cryptedKey.
+func (c *passworkClient) encryptPassword(ctx context.Context, vaultId, cryptedKey, password string) (string, error) {
+	if password == "" {
+		return "", nil
+	}
+
+	if !c.clientSideEncryption {
+		return base64.StdEncoding.EncodeToString([]byte(password)), nil
+	}
+
+	key, err := c.passwordKey(ctx, vaultId, cryptedKey)
+	if err != nil {
+		return "", err
+	}
+
+	return encryptString(password, key)
+}
+
+// entryKeyMoveError is returned, if an entry with its own key is moved to another vault.
+type entryKeyMoveError struct {
+	id      string
+	vaultId string
+}
+
+func (e *entryKeyMoveError) Error() string {
+	return fmt.Sprintf("the password entry %s has its own key, which is encrypted with the key of vault %s. "+
+		"The API keeps this key, when the entry is updated, so the entry cannot be moved to another vault with client-side encryption", e.id, e.vaultId)
+}
+
+// entryKey returns the encrypted key of an existing entry, which must be used to encrypt its new
+// password, as the API keeps the key of the entry on updates. It is empty, if the entry is
+// encrypted with the vault key or client-side encryption is disabled.
+func (c *passworkClient) entryKey(ctx context.Context, id, vaultId string) (string, error) {
+	if !c.clientSideEncryption {
+		return "", nil
+	}
+
+	current, err := c.GetPassword(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	if current.Data.CryptedKey != "" && current.Data.VaultId != vaultId {
+		return "", &entryKeyMoveError{id: id, vaultId: current.Data.VaultId}
+	}
+
+	return current.Data.CryptedKey, nil
+}
+
+// decryptPassword decrypts the value of a password entry returned by the API.
+func (c *passworkClient) decryptPassword(ctx context.Context, data passwork.PasswordResponseData) (string, error) {
+	if data.CryptedPassword == "" {
+		return "", nil
+	}
+
+	if !c.clientSideEncryption {
+		password, err := base64.StdEncoding.DecodeString(data.CryptedPassword)
+		return string(password), err
+	}
+
+	key, err := c.passwordKey(ctx, data.VaultId, data.CryptedKey)
+	if err != nil {
+		return "", err
+	}
+
+	return decryptString(data.CryptedPassword, key)
+}
+
+// cachedVault returns the vault, reading it from the API only once.
+func (c *passworkClient) cachedVault(ctx context.Context, vaultId string) (passwork.VaultResponseData, error) {
+	return c.vaultCache.get(vaultId, func() (passwork.VaultResponseData, error) {
+		response, err := c.GetVault(ctx, vaultId)
+		return response.Data, err
+	})
+}
+
+// cachedFolder returns the folder, reading it from the API only once.
+func (c *passworkClient) cachedFolder(ctx context.Context, folderId string) (passwork.FolderResponseData, error) {
+	return c.folderCache.get(folderId, func() (passwork.FolderResponseData, error) {
+		response, err := c.GetFolder(ctx, folderId)
+		return response.Data, err
+	})
+}
@@ -0,0 +1,56 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestObjectCache(t *testing.T) {
+	var cache objectCache[string]
+	var reads, active, maxActive int32
+
+	read := func(id string) func() (string, error) {
+		return func() (string, error) {
+			atomic.AddInt32(&reads, 1)
+			if current := atomic.AddInt32(&active, 1); current > atomic.LoadInt32(&maxActive) {
+				atomic.StoreInt32(&maxActive, current)
+			}
+			time.Sleep(20 * time.Millisecond)
+			atomic.AddInt32(&active, -1)
+			return "object " + id, nil
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, id := range []string{"a", "a", "a", "b", "b", "c"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if object, err := cache.get(id, read(id)); err != nil || object != "object "+id {
+				t.Errorf("unexpected object %q (%v)", object, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if reads != 3 {
+		t.Fatalf("expected a single read per Id, got %d reads", reads)
+	}
+	if maxActive < 2 {
+		t.Fatalf("expected different Ids to be read in parallel, got %d concurrent reads", maxActive)
+	}
+
+	// Errors are not cached
+	if _, err := cache.get("d", func() (string, error) { return "", errors.New("failed") }); err == nil {
+		t.Fatal("expected error")
+	}
+	if object, err := cache.get("d", read("d")); err != nil || object != "object d" {
+		t.Fatalf("expected the object to be read again, got %q (%v)", object, err)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// concurrencyTransport limits the number of requests sent to Passwork at the same time. A slot
+// is held until the response body is closed, so reading a response counts as part of the request.
+type concurrencyTransport struct {
+	base      http.RoundTripper
+	semaphore chan struct{}
+}
+
+func newConcurrencyTransport(base http.RoundTripper, maxConcurrentRequests int) http.RoundTripper {
+	if maxConcurrentRequests <= 0 {
+		return base
+	}
+
+	return &concurrencyTransport{
+		base:      base,
+		semaphore: make(chan struct{}, maxConcurrentRequests),
+	}
+}
+
+func (t *concurrencyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case t.semaphore <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+
+	release := sync.OnceFunc(func() { <-t.semaphore })
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// releasingBody frees the slot of a request, once its response body is closed.
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
+
+// vaultLocks serializes write operations within the same vault. Reads are not affected.
+type vaultLocks struct {
+	mutex sync.Mutex
+	// locks hold a value while the vault is locked, so waiting for them can be cancelled.
+	locks map[string]chan struct{}
+}
+
+// lockVault locks the vault for writing and returns the function to unlock it. If
+// serializing writes is disabled, no lock is taken. Waiting for the lock stops, when
+// ctx is cancelled.
+func (c *passworkClient) lockVault(ctx context.Context, vaultId string) (func(), error) {
+	if !c.serializeVaultWrites || vaultId == "" {
+		return func() {}, nil
+	}
+
+	c.vaultLocks.mutex.Lock()
+	if c.vaultLocks.locks == nil {
+		c.vaultLocks.locks = map[string]chan struct{}{}
+	}
+	lock, ok := c.vaultLocks.locks[vaultId]
+	if !ok {
+		lock = make(chan struct{}, 1)
+		c.vaultLocks.locks[vaultId] = lock
+	}
+	c.vaultLocks.mutex.Unlock()
+
+	select {
+	case lock <- struct{}{}:
+		return func() { <-lock }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for other writes in vault %s: %w", vaultId, ctx.Err())
+	}
+}
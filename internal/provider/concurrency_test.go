@@ -0,0 +1,98 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestConcurrencyTransport(t *testing.T) {
+	var active, maxActive int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&active, 1)
+		defer atomic.AddInt32(&active, -1)
+		for {
+			previous := atomic.LoadInt32(&maxActive)
+			if current <= previous || atomic.CompareAndSwapInt32(&maxActive, previous, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newConcurrencyTransport(http.DefaultTransport, 2)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Errorf("unexpected error: %s", err)
+				return
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if maxActive > 2 {
+		t.Fatalf("expected at most 2 concurrent requests, got %d", maxActive)
+	}
+}
+
+func TestLockVault(t *testing.T) {
+	client := newPassworkClient(nil, false, "")
+	client.serializeVaultWrites = true
+
+	var active, maxActive int32
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := client.lockVault(context.Background(), "vault")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer unlock()
+
+			if current := atomic.AddInt32(&active, 1); current > atomic.LoadInt32(&maxActive) {
+				atomic.StoreInt32(&maxActive, current)
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&active, -1)
+		}()
+	}
+	wg.Wait()
+
+	if maxActive != 1 {
+		t.Fatalf("expected writes to the same vault to be serialized, got %d concurrent writes", maxActive)
+	}
+
+	unlock, err := client.lockVault(context.Background(), "vault")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.lockVault(ctx, "vault"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected waiting for the lock to be cancelled, got %v", err)
+	}
+}
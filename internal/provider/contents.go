@@ -0,0 +1,173 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/lupa95/passwork-client-go"
+)
+
+// maxDescribedContents limits the number of folders and passwords listed in diagnostics.
+const maxDescribedContents = 20
+
+// contentFolder is a folder found inside a vault or folder.
+type contentFolder struct {
+	passwork.FolderResponseData
+	// path is the path of the folder relative to the vault or folder containing it.
+	path  string
+	depth int
+}
+
+// contentPassword is a password found inside a vault or folder.
+type contentPassword struct {
+	passwork.PasswordResponseData
+	// folderPath is the path of the folder containing the password, relative to the vault or folder.
+	folderPath string
+}
+
+// contents are all folders and passwords inside a vault or folder, including nested ones.
+type contents struct {
+	// folders are ordered deepest first, so they can be deleted in this order.
+	folders   []contentFolder
+	passwords []contentPassword
+}
+
+func (c contents) empty() bool {
+	return len(c.folders) == 0 && len(c.passwords) == 0
+}
+
+// summary returns the number of folders and passwords, e.g. "2 folders and 5 passwords".
+func (c contents) summary() string {
+	return fmt.Sprintf("%d folders and %d passwords", len(c.folders), len(c.passwords))
+}
+
+// describe lists the folders and passwords for diagnostics.
+func (c contents) describe() string {
+	var lines []string
+	for i := len(c.folders) - 1; i >= 0; i-- {
+		lines = append(lines, fmt.Sprintf("- folder %q (%s)", c.folders[i].path, c.folders[i].Id))
+	}
+	for _, password := range c.passwords {
+		name := password.Name
+		if password.folderPath != "" {
+			name = password.folderPath + "/" + name
+		}
+		lines = append(lines, fmt.Sprintf("- password %q (%s)", name, password.Id))
+	}
+
+	if len(lines) > maxDescribedContents {
+		lines = append(lines[:maxDescribedContents], fmt.Sprintf("- and %d more", len(lines)-maxDescribedContents))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// listContents returns all folders and passwords inside the folder, or inside the vault, if folderId is empty.
+func (c *passworkClient) listContents(ctx context.Context, vaultId, folderId string) (contents, error) {
+	var result contents
+
+	type parent struct {
+		id    string
+		path  string
+		depth int
+	}
+	queue := []parent{{id: folderId}}
+
+	for len(queue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		current := queue[0]
+		queue = queue[1:]
+
+		passwords, err := c.ListPasswords(ctx, vaultId, current.id)
+		if err != nil {
+			return result, err
+		}
+		for _, password := range passwords {
+			result.passwords = append(result.passwords, contentPassword{PasswordResponseData: password, folderPath: current.path})
+		}
+
+		folders, err := c.ListFolders(ctx, vaultId, current.id)
+		if err != nil {
+			return result, err
+		}
+		for _, folder := range folders {
+			path := folder.Name
+			if current.path != "" {
+				path = current.path + "/" + folder.Name
+			}
+			result.folders = append(result.folders, contentFolder{FolderResponseData: folder, path: path, depth: current.depth + 1})
+			queue = append(queue, parent{id: folder.Id, path: path, depth: current.depth + 1})
+		}
+	}
+
+	sort.SliceStable(result.folders, func(i, j int) bool {
+		return result.folders[i].depth > result.folders[j].depth
+	})
+
+	return result, nil
+}
+
+// deleteContents deletes the passwords and then the folders, deepest folder first, and returns
+// the deleted folders and passwords, also when it fails. Objects, which no longer exist, are
+// treated as deleted. Each deleted object is logged at info level,
+// as deleting large vaults can take a while.
+func (c *passworkClient) deleteContents(ctx context.Context, contents contents) (deleted contents, err error) {
+	total := len(contents.passwords) + len(contents.folders)
+	count := func() int {
+		return len(deleted.passwords) + len(deleted.folders)
+	}
+
+	checkContext := func() error {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("stopped after deleting %d of %d objects: %w", count(), total, err)
+		}
+		return nil
+	}
+
+	for _, password := range contents.passwords {
+		if err := checkContext(); err != nil {
+			return deleted, err
+		}
+		if _, err := c.DeletePassword(ctx, password.Id); err != nil && !isNotFound(err) {
+			return deleted, fmt.Errorf("could not delete password %q (%s) after deleting %d of %d objects: %w", password.Name, password.Id, count(), total, err)
+		}
+		deleted.passwords = append(deleted.passwords, password)
+		tflog.Info(ctx, fmt.Sprintf("Deleted password %q (%d/%d)", password.Name, count(), total), map[string]interface{}{
+			"password_id": password.Id,
+		})
+	}
+
+	for _, folder := range contents.folders {
+		if err := checkContext(); err != nil {
+			return deleted, err
+		}
+		if _, err := c.DeleteFolder(ctx, folder.Id); err != nil && !isNotFound(err) {
+			return deleted, fmt.Errorf("could not delete folder %q (%s) after deleting %d of %d objects: %w", folder.path, folder.Id, count(), total, err)
+		}
+		deleted.folders = append(deleted.folders, folder)
+		tflog.Info(ctx, fmt.Sprintf("Deleted folder %q (%d/%d)", folder.path, count(), total), map[string]interface{}{
+			"folder_id": folder.Id,
+		})
+	}
+
+	return deleted, nil
+}
+
+// describeDeleted lists the folders and passwords, which were deleted before deleting the contents
+// of a vault or folder failed, so the diagnostic shows the progress.
+func describeDeleted(deleted contents) string {
+	if deleted.empty() {
+		return "\n\nNo folders or passwords were deleted."
+	}
+
+	return "\n\nThe following " + deleted.summary() + " were deleted before the error:\n\n" + deleted.describe()
+}
@@ -0,0 +1,118 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lupa95/passwork-client-go"
+)
+
+func TestContents(t *testing.T) {
+	responses := map[string]string{
+		"GET /api/v4/vaults/vault/folders":     `[{"id":"a","name":"A"}]`,
+		"GET /api/v4/vaults/vault/passwords":   `[{"id":"p1","name":"root password"}]`,
+		"GET /api/v4/folders/a/children":       `[{"id":"b","name":"B"}]`,
+		"GET /api/v4/folders/a/passwords":      `[]`,
+		"GET /api/v4/folders/b/children":       `[]`,
+		"GET /api/v4/folders/b/passwords":      `[{"id":"p2","name":"nested password"}]`,
+		"DELETE /api/v4/folders/a":             `"folderDeleted"`,
+		"DELETE /api/v4/folders/b":             `"folderDeleted"`,
+		"DELETE /api/v4/passwords/p1":          `"passwordDeleted"`,
+		"DELETE /api/v4/passwords/p2":          `"passwordDeleted"`,
+		"GET /api/v4/folders/missing/children": ``,
+	}
+
+	var (
+		mutex   sync.Mutex
+		deleted []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		data, ok := responses[r.Method+" "+r.URL.Path]
+		mutex.Unlock()
+		if !ok || data == "" {
+			fmt.Fprint(w, `{"status":"error","code":"folderNotFound"}`)
+			return
+		}
+		if data == "denied" {
+			fmt.Fprint(w, `{"status":"error","code":"accessDenied"}`)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			mutex.Lock()
+			deleted = append(deleted, r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+			mutex.Unlock()
+		}
+		fmt.Fprintf(w, `{"status":"success","data":%s}`, data)
+	}))
+	defer server.Close()
+
+	client := newPassworkClient(newV4Backend(passwork.NewClient(server.URL+"/api/v4", "test-key", 5*time.Second)), false, "")
+	ctx := context.Background()
+
+	contents, err := client.listContents(ctx, "vault", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if contents.summary() != "2 folders and 2 passwords" {
+		t.Fatalf("unexpected contents: %s", contents.summary())
+	}
+	if !strings.Contains(contents.describe(), `password "A/B/nested password" (p2)`) {
+		t.Fatalf("expected nested password with path, got:\n%s", contents.describe())
+	}
+
+	deletedContents, err := client.deleteContents(ctx, contents)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if deletedContents.summary() != contents.summary() {
+		t.Fatalf("expected all contents to be deleted, got %s", deletedContents.summary())
+	}
+	if strings.Join(deleted, ",") != "p1,p2,b,a" {
+		t.Fatalf("expected passwords and then the deepest folder to be deleted first, got %v", deleted)
+	}
+
+	mutex.Lock()
+	responses["DELETE /api/v4/folders/a"] = "denied"
+	mutex.Unlock()
+	deletedContents, err = client.deleteContents(ctx, contents)
+	if err == nil || !strings.Contains(err.Error(), `could not delete folder "A" (a) after deleting 3 of 4 objects`) {
+		t.Fatalf("expected deleting folder A to fail, got %v", err)
+	}
+	if detail := describeDeleted(deletedContents); !strings.Contains(detail, "1 folders and 2 passwords were deleted") || !strings.Contains(detail, `folder "A/B" (b)`) {
+		t.Fatalf("expected the deleted objects to be listed, got:\n%s", detail)
+	}
+
+	if _, err := client.listContents(ctx, "vault", "missing"); err == nil || err.Error() != "folderNotFound" {
+		t.Fatalf("expected folderNotFound error, got %v", err)
+	}
+}
+
+func TestFolderNotEmptyError(t *testing.T) {
+	contents := contents{
+		folders:   []contentFolder{{FolderResponseData: passwork.FolderResponseData{Id: "b"}, path: "B"}},
+		passwords: []contentPassword{{PasswordResponseData: passwork.PasswordResponseData{Id: "p", Name: "database"}}},
+	}
+
+	summary, detail := folderNotEmptyError("a", contents)
+	if summary != "Passwork Folder Not Empty" {
+		t.Fatalf("unexpected summary %q", summary)
+	}
+	for _, expected := range []string{"still contains 1 folders and 1 passwords", `folder "B" (b)`, "use the Id of the folder as literal value", "recursive_delete = true"} {
+		if !strings.Contains(detail, expected) {
+			t.Fatalf("expected detail to contain %q, got:\n%s", expected, detail)
+		}
+	}
+	if strings.Contains(detail, "not managed by this Terraform configuration") {
+		t.Fatalf("expected the objects not to be reported as unmanaged, got:\n%s", detail)
+	}
+}
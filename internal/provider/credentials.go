@@ -0,0 +1,207 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	defaultProfile        = "default"
+	apiKeyCommandTimeout  = 30 * time.Second
+	credentialsFileSuffix = ".config/passwork/credentials"
+)
+
+// credentialChain resolves the Passwork API key. The sources are checked in order:
+// explicit configuration, environment, profiles file and finally an external command.
+type credentialChain struct {
+	configAPIKey    string
+	envAPIKey       string
+	profile         string
+	profileExplicit bool
+	credentialsFile string
+	apiKeyCommand   string
+}
+
+// credentials is the result of resolving the credential chain.
+type credentials struct {
+	apiKey string
+	// host is only set, if the selected profile contains a host. It is set regardless of the
+	// source of the API key.
+	host string
+	// source describes where the API key was found.
+	source string
+	// skipped describes for every source checked before, why it was not used.
+	skipped []string
+}
+
+// resolve returns the API key of the first source providing one. An error is returned,
+// if a source is configured but fails, e.g. if a selected profile does not exist.
+func (c credentialChain) resolve(ctx context.Context) (credentials, error) {
+	var result credentials
+
+	profile, err := c.readProfile()
+	if err != nil {
+		return result, err
+	}
+	result.host = profile["host"]
+
+	if c.configAPIKey != "" {
+		result.apiKey, result.source = c.configAPIKey, "the api_key provider attribute"
+		return result, nil
+	}
+	result.skipped = append(result.skipped, "api_key provider attribute: not set")
+
+	if c.envAPIKey != "" {
+		result.apiKey, result.source = c.envAPIKey, "the PASSWORK_API_KEY environment variable"
+		return result, nil
+	}
+	result.skipped = append(result.skipped, "PASSWORK_API_KEY environment variable: not set")
+
+	if profile["api_key"] != "" {
+		result.apiKey = profile["api_key"]
+		result.source = fmt.Sprintf("profile %q in the credentials file %s", c.profile, c.credentialsFile)
+		return result, nil
+	}
+	switch {
+	case profile != nil:
+		result.skipped = append(result.skipped, fmt.Sprintf("credentials file %s: profile %q does not contain an api_key", c.credentialsFile, c.profile))
+	case c.credentialsFile == "":
+		result.skipped = append(result.skipped, "credentials file: home directory could not be determined")
+	default:
+		result.skipped = append(result.skipped, fmt.Sprintf("credentials file %s: does not exist", c.credentialsFile))
+	}
+
+	if c.apiKeyCommand == "" {
+		result.skipped = append(result.skipped, "api_key_command: not set")
+		return result, nil
+	}
+
+	result.apiKey, err = runAPIKeyCommand(ctx, c.apiKeyCommand)
+	if err != nil {
+		return result, err
+	}
+	result.source = "the output of api_key_command"
+
+	return result, nil
+}
+
+// readProfile returns the selected profile from the credentials file. It returns nil, if the
+// file does not exist or the default profile is missing. A missing, explicitly selected profile is an error.
+func (c credentialChain) readProfile() (map[string]string, error) {
+	if c.credentialsFile == "" {
+		return nil, nil
+	}
+
+	profiles, err := parseCredentialsFile(c.credentialsFile)
+	if errors.Is(err, os.ErrNotExist) {
+		if c.profileExplicit {
+			return nil, fmt.Errorf("profile %q is selected, but the credentials file %s does not exist", c.profile, c.credentialsFile)
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	profile, ok := profiles[c.profile]
+	if !ok {
+		if c.profileExplicit {
+			return nil, fmt.Errorf("profile %q does not exist in the credentials file %s", c.profile, c.credentialsFile)
+		}
+		return map[string]string{}, nil
+	}
+
+	return profile, nil
+}
+
+// defaultCredentialsFile returns the path of the credentials file in the user's home directory.
+func defaultCredentialsFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, filepath.FromSlash(credentialsFileSuffix))
+}
+
+// parseCredentialsFile parses an INI style file with one section per profile:
+//
+//	[default]
+//	host    = https://my-passwork-instance.com
+//	api_key = my-api-key
+func parseCredentialsFile(path string) (map[string]map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	profiles := map[string]map[string]string{}
+	var current map[string]string
+
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			current = map[string]string{}
+			profiles[name] = current
+		default:
+			key, value, ok := strings.Cut(line, "=")
+			if !ok || current == nil {
+				return nil, fmt.Errorf("invalid syntax in credentials file %s on line %d", path, lineNumber)
+			}
+			current[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return profiles, nil
+}
+
+// runAPIKeyCommand runs the command in the system shell and returns its output as API key.
+func runAPIKeyCommand(ctx context.Context, command string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, apiKeyCommandTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("api_key_command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	apiKey := strings.TrimSpace(stdout.String())
+	if apiKey == "" {
+		return "", errors.New("api_key_command did not print an API key to stdout")
+	}
+
+	return apiKey, nil
+}
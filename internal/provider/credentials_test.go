@@ -0,0 +1,110 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestCredentialChain(t *testing.T) {
+	credentialsFile := filepath.Join(t.TempDir(), "credentials")
+	err := os.WriteFile(credentialsFile, []byte(`
+# Passwork credentials
+[default]
+api_key = default-key
+
+[staging]
+host    = https://staging.example.com
+api_key = "staging-key"
+
+[empty]
+host = https://empty.example.com
+`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type testCase struct {
+		chain          credentialChain
+		expectedAPIKey string
+		expectedHost   string
+		expectedError  bool
+	}
+
+	testCases := map[string]testCase{
+		"config": {
+			chain:          credentialChain{configAPIKey: "config-key", envAPIKey: "env-key", profile: defaultProfile, credentialsFile: credentialsFile},
+			expectedAPIKey: "config-key",
+		},
+		"environment": {
+			chain:          credentialChain{envAPIKey: "env-key", profile: defaultProfile, credentialsFile: credentialsFile},
+			expectedAPIKey: "env-key",
+		},
+		"environment with profile host": {
+			chain:          credentialChain{envAPIKey: "env-key", profile: "staging", profileExplicit: true, credentialsFile: credentialsFile},
+			expectedAPIKey: "env-key",
+			expectedHost:   "https://staging.example.com",
+		},
+		"default profile": {
+			chain:          credentialChain{profile: defaultProfile, credentialsFile: credentialsFile},
+			expectedAPIKey: "default-key",
+		},
+		"selected profile": {
+			chain:          credentialChain{profile: "staging", profileExplicit: true, credentialsFile: credentialsFile},
+			expectedAPIKey: "staging-key",
+			expectedHost:   "https://staging.example.com",
+		},
+		"missing profile": {
+			chain:         credentialChain{profile: "production", profileExplicit: true, credentialsFile: credentialsFile},
+			expectedError: true,
+		},
+		"missing credentials file": {
+			chain: credentialChain{profile: defaultProfile, credentialsFile: filepath.Join(t.TempDir(), "missing")},
+		},
+		"profile without api_key": {
+			chain:        credentialChain{profile: "empty", profileExplicit: true, credentialsFile: credentialsFile},
+			expectedHost: "https://empty.example.com",
+		},
+	}
+
+	if runtime.GOOS != "windows" {
+		testCases["command"] = testCase{
+			chain:          credentialChain{profile: "empty", credentialsFile: credentialsFile, apiKeyCommand: "echo command-key"},
+			expectedAPIKey: "command-key",
+			expectedHost:   "https://empty.example.com",
+		}
+		testCases["failing command"] = testCase{
+			chain:         credentialChain{profile: "empty", credentialsFile: credentialsFile, apiKeyCommand: "exit 1"},
+			expectedError: true,
+		}
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			credentials, err := testCase.chain.resolve(context.Background())
+			if testCase.expectedError {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if credentials.apiKey != testCase.expectedAPIKey {
+				t.Errorf("expected API key %q, got %q", testCase.expectedAPIKey, credentials.apiKey)
+			}
+			if credentials.host != testCase.expectedHost {
+				t.Errorf("expected host %q, got %q", testCase.expectedHost, credentials.host)
+			}
+			if credentials.apiKey == "" && len(credentials.skipped) != 4 {
+				t.Errorf("expected all sources to be skipped, got %v", credentials.skipped)
+			}
+		})
+	}
+}
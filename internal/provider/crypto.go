@@ -0,0 +1,130 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// Passwork encrypts secrets in the browser with CryptoJS.AES using a passphrase.
+// The resulting ciphertext is the OpenSSL compatible format: base64("Salted__" + salt + AES-256-CBC(data)),
+// where key and IV are derived from the passphrase and salt with EVP_BytesToKey (MD5, one iteration).
+
+const (
+	cipherSaltHeader = "Salted__"
+	cipherSaltSize   = 8
+	cipherKeySize    = 32
+)
+
+// encryptString encrypts data with the given passphrase the same way Passwork does on the client side.
+func encryptString(data, passphrase string) (string, error) {
+	salt := make([]byte, cipherSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	return encryptStringWithSalt(data, passphrase, salt)
+}
+
+func encryptStringWithSalt(data, passphrase string, salt []byte) (string, error) {
+	key, iv := evpBytesToKey([]byte(passphrase), salt)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext := pkcs7Pad([]byte(data), aes.BlockSize)
+	ciphertext := make([]byte, len(plaintext))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, plaintext)
+
+	var result bytes.Buffer
+	result.WriteString(cipherSaltHeader)
+	result.Write(salt)
+	result.Write(ciphertext)
+
+	return base64.StdEncoding.EncodeToString(result.Bytes()), nil
+}
+
+// decryptString decrypts a value, which was encrypted by Passwork on the client side.
+func decryptString(data, passphrase string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return "", fmt.Errorf("could not decode encrypted value: %w", err)
+	}
+
+	headerSize := len(cipherSaltHeader) + cipherSaltSize
+	if len(raw) < headerSize+aes.BlockSize || string(raw[:len(cipherSaltHeader)]) != cipherSaltHeader {
+		return "", errors.New("encrypted value has an unexpected format")
+	}
+
+	salt := raw[len(cipherSaltHeader):headerSize]
+	ciphertext := raw[headerSize:]
+	if len(ciphertext)%aes.BlockSize != 0 {
+		return "", errors.New("encrypted value has an unexpected length")
+	}
+
+	key, iv := evpBytesToKey([]byte(passphrase), salt)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	plaintext, err = pkcs7Unpad(plaintext, aes.BlockSize)
+	if err != nil {
+		return "", errors.New("could not decrypt value, the master password is probably wrong")
+	}
+
+	return string(plaintext), nil
+}
+
+// evpBytesToKey implements OpenSSL's EVP_BytesToKey with MD5 and a single iteration.
+func evpBytesToKey(passphrase, salt []byte) (key, iv []byte) {
+	var derived, block []byte
+
+	for len(derived) < cipherKeySize+aes.BlockSize {
+		hash := md5.New()
+		hash.Write(block)
+		hash.Write(passphrase)
+		hash.Write(salt)
+		block = hash.Sum(nil)
+		derived = append(derived, block...)
+	}
+
+	return derived[:cipherKeySize], derived[cipherKeySize : cipherKeySize+aes.BlockSize]
+}
+
+func pkcs7Pad(data []byte, blockSize int) []byte {
+	padding := blockSize - len(data)%blockSize
+	return append(data, bytes.Repeat([]byte{byte(padding)}, padding)...)
+}
+
+func pkcs7Unpad(data []byte, blockSize int) ([]byte, error) {
+	if len(data) == 0 || len(data)%blockSize != 0 {
+		return nil, errors.New("invalid padding")
+	}
+
+	padding := int(data[len(data)-1])
+	if padding == 0 || padding > blockSize {
+		return nil, errors.New("invalid padding")
+	}
+
+	for _, b := range data[len(data)-padding:] {
+		if int(b) != padding {
+			return nil, errors.New("invalid padding")
+		}
+	}
+
+	return data[:len(data)-padding], nil
+}
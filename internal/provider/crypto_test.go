@@ -0,0 +1,192 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/lupa95/passwork-client-go"
+)
+
+// Fixtures are in the CryptoJS/OpenSSL format used by Passwork for client-side encryption. They were
+// created with OpenSSL independently of the provider, which uses the same format as CryptoJS.AES with a
+// passphrase, e.g. with a fixed salt (OpenSSL 3 omits the "Salted__" header then):
+//
+//	echo -n vault-secret | openssl enc -aes-256-cbc -md md5 -S 1112131415161718 -pass pass:master-password -base64 -A
+const (
+	testMasterPassword       = "master-password"
+	testVaultPasswordCrypted = "U2FsdGVkX18REhMUFRYXGCHlS+oYJ7LouO5ceolKHWU="
+	testVaultPassword        = "vault-secret"
+	testCryptedPassword      = "U2FsdGVkX18BAgMEBQYHCDU4nfRUBoq90Aje8QqZbJoKUOzFYEcd9HVfvIVKOBo5"
+	testPassword             = "provider-test-password"
+
+	// echo -n "Passwork 4 entry" | openssl enc -aes-256-cbc -md md5 -pass pass:vault-secret -base64 -A
+	testOpenSSLCrypted = "U2FsdGVkX18LwI3u1JXIf1n0f3DGaP8Na7RWmTr55D++HnDbSV8U+H+P2KmF6kt0"
+	testOpenSSLPlain   = "Passwork 4 entry"
+)
+
+func TestDecryptString(t *testing.T) {
+	vaultPassword, err := decryptString(testVaultPasswordCrypted, testMasterPassword)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if vaultPassword != testVaultPassword {
+		t.Fatalf("expected %q, got %q", testVaultPassword, vaultPassword)
+	}
+
+	password, err := decryptString(testCryptedPassword, vaultPassword)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if password != testPassword {
+		t.Fatalf("expected %q, got %q", testPassword, password)
+	}
+
+	// Random salt chosen by OpenSSL
+	plain, err := decryptString(testOpenSSLCrypted, testVaultPassword)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if plain != testOpenSSLPlain {
+		t.Fatalf("expected %q, got %q", testOpenSSLPlain, plain)
+	}
+
+	if _, err := decryptString(testCryptedPassword, "wrong-password"); err == nil {
+		t.Fatal("expected error when decrypting with wrong passphrase")
+	}
+}
+
+func TestEncryptString(t *testing.T) {
+	crypted, err := encryptStringWithSalt(testPassword, testVaultPassword, []byte{1, 2, 3, 4, 5, 6, 7, 8})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if crypted != testCryptedPassword {
+		t.Fatalf("expected %q, got %q", testCryptedPassword, crypted)
+	}
+
+	crypted, err = encryptString(testPassword, testVaultPassword)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	decrypted, err := decryptString(crypted, testVaultPassword)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if decrypted != testPassword {
+		t.Fatalf("expected %q, got %q", testPassword, decrypted)
+	}
+}
+
+func TestClientSideEncryptionPassword(t *testing.T) {
+	client := newPassworkClient(nil, true, testMasterPassword)
+	client.vaultKeys.set("vault", testVaultPassword)
+
+	password, err := client.decryptPassword(context.Background(), passwork.PasswordResponseData{
+		VaultId:         "vault",
+		CryptedPassword: testCryptedPassword,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if password != testPassword {
+		t.Fatalf("expected %q, got %q", testPassword, password)
+	}
+
+	// Entries with their own key store it encrypted with the vault key
+	cryptedKey, err := encryptString("entry-key", testVaultPassword)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	cryptedPassword, err := encryptString(testPassword, "entry-key")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	password, err = client.decryptPassword(context.Background(), passwork.PasswordResponseData{
+		VaultId:         "vault",
+		CryptedKey:      cryptedKey,
+		CryptedPassword: cryptedPassword,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if password != testPassword {
+		t.Fatalf("expected %q, got %q", testPassword, password)
+	}
+}
+
+func TestClientSideEncryptionRequest(t *testing.T) {
+	client := newPassworkClient(nil, true, testMasterPassword)
+	client.vaultKeys.set("vault", testVaultPassword)
+
+	request, err := PasswordModelToRequest(context.Background(), PasswordResourceModel{
+		VaultId:  types.StringValue("vault"),
+		Name:     types.StringValue("test"),
+		Password: types.StringValue(testPassword),
+	}, client, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	password, err := decryptString(request.CryptedPassword, testVaultPassword)
+	if err != nil || password != testPassword {
+		t.Fatalf("expected the password to be encrypted with the vault key, got %q (%v)", password, err)
+	}
+
+	// Custom fields and attachments are not managed, so they are never sent unencrypted
+	if request.Custom != nil || request.Attachments != nil {
+		t.Fatalf("expected no custom fields and attachments, got %v and %v", request.Custom, request.Attachments)
+	}
+}
+
+func TestClientSideEncryptionEntryKey(t *testing.T) {
+	cryptedKey, err := encryptString("entry-key", testVaultPassword)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"status":"success","data":{"id":"entry","vaultId":"vault","cryptedKey":%q}}`, cryptedKey)
+	}))
+	defer server.Close()
+
+	client := newPassworkClient(newV4Backend(passwork.NewClient(server.URL+"/api/v4", "test-key", 5*time.Second)), true, testMasterPassword)
+	client.vaultKeys.set("vault", testVaultPassword)
+	ctx := context.Background()
+
+	key, err := client.entryKey(ctx, "entry", "vault")
+	if err != nil || key != cryptedKey {
+		t.Fatalf("expected the key of the entry, got %q (%v)", key, err)
+	}
+
+	// The new password must be readable with the key, which the API keeps
+	request, err := PasswordModelToRequest(ctx, PasswordResourceModel{
+		VaultId:  types.StringValue("vault"),
+		Name:     types.StringValue("test"),
+		Password: types.StringValue(testPassword),
+	}, client, key)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	password, err := client.decryptPassword(ctx, passwork.PasswordResponseData{
+		VaultId:         "vault",
+		CryptedKey:      cryptedKey,
+		CryptedPassword: request.CryptedPassword,
+	})
+	if err != nil || password != testPassword {
+		t.Fatalf("expected the password to be encrypted with the entry key, got %q (%v)", password, err)
+	}
+
+	var moveErr *entryKeyMoveError
+	if _, err := client.entryKey(ctx, "entry", "other"); !errors.As(err, &moveErr) {
+		t.Fatalf("expected entryKeyMoveError, got %v", err)
+	}
+}
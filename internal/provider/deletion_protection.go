@@ -0,0 +1,32 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// localBoolState returns the value to keep in state for flags like deletion_protection. These
+// are not stored in Passwork, so they are unknown for imported resources and default to false.
+func localBoolState(value types.Bool) types.Bool {
+	if value.IsNull() || value.IsUnknown() {
+		return types.BoolValue(false)
+	}
+
+	return value
+}
+
+// addDeletionProtectionError adds the error for deleting a protected object. kind is e.g. Vault or Password.
+func addDeletionProtectionError(diags *diag.Diagnostics, kind, id string) {
+	diags.AddError(
+		"Passwork "+kind+" Is Protected From Deletion",
+		fmt.Sprintf("The %s %s can not be deleted, as deletion_protection is enabled. ", strings.ToLower(kind), id)+
+			"To delete it, set deletion_protection = false in the configuration and apply the change first. "+
+			"Then destroy the resource or remove it from the configuration.",
+	)
+}
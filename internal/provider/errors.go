@@ -0,0 +1,194 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// errorKind classifies errors of the Passwork API, so they are reported with consistent diagnostics.
+type errorKind int
+
+const (
+	errorKindUnknown errorKind = iota
+	errorKindAuth
+	errorKindForbidden
+	errorKindNotFound
+	errorKindValidation
+	errorKindConflict
+	errorKindRateLimit
+	errorKindServer
+)
+
+// apiError is an error response of the Passwork API. Its message is the error code of
+// the response, e.g. accessDenied, as returned by the Passwork client.
+type apiError struct {
+	kind       errorKind
+	statusCode int
+	err        error
+}
+
+func (e *apiError) Error() string {
+	return e.err.Error()
+}
+
+func (e *apiError) Unwrap() error {
+	return e.err
+}
+
+// newAPIError classifies an error returned for a response with the given HTTP status code.
+// The status code is 0, if it is not known.
+func newAPIError(statusCode int, err error) *apiError {
+	kind := errorKindFromStatus(statusCode)
+	// The Passwork client returns the error code of the response as unwrapped error
+	if kind == errorKindUnknown && errors.Unwrap(err) == nil {
+		kind = errorKindFromCode(err.Error())
+	}
+
+	return &apiError{kind: kind, statusCode: statusCode, err: err}
+}
+
+// errorKindFromStatus classifies the HTTP status code. 404 is not classified as not found, as it is
+// also returned by proxies or gateways for wrong routes. Only the error code of the API is trusted,
+// so objects are never removed from the state, because Passwork could not be reached.
+func errorKindFromStatus(statusCode int) errorKind {
+	switch {
+	case statusCode == http.StatusUnauthorized:
+		return errorKindAuth
+	case statusCode == http.StatusForbidden:
+		return errorKindForbidden
+	case statusCode == http.StatusBadRequest || statusCode == http.StatusUnprocessableEntity:
+		return errorKindValidation
+	case statusCode == http.StatusConflict:
+		return errorKindConflict
+	case statusCode == http.StatusTooManyRequests:
+		return errorKindRateLimit
+	case statusCode >= http.StatusInternalServerError:
+		return errorKindServer
+	}
+
+	return errorKindUnknown
+}
+
+// errorCodeKinds are the known error codes of the Passwork API. Other codes are not classified,
+// so an object is only removed from the state for a code, which is known to mean not found.
+var errorCodeKinds = map[string]errorKind{
+	"passwordNull":   errorKindNotFound,
+	"folderNotFound": errorKindNotFound,
+	"vaultNotFound":  errorKindNotFound,
+	"notFound":       errorKindNotFound,
+	"accessDenied":   errorKindForbidden,
+	"nameRequired":   errorKindValidation,
+}
+
+// errorKindFromCode classifies the error codes of the Passwork API, e.g. accessDenied or passwordNull.
+func errorKindFromCode(code string) errorKind {
+	if kind, ok := errorCodeKinds[code]; ok {
+		return kind
+	}
+
+	// Codes of an expired session and failed logins of the session transport
+	if sessionExpiredCodes[code] || strings.HasPrefix(code, "login failed") {
+		return errorKindAuth
+	}
+
+	return errorKindUnknown
+}
+
+// errorKindOf returns the kind of an error returned by the backend.
+func errorKindOf(err error) errorKind {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		return apiErr.kind
+	}
+
+	// Responses, which were still transient after the last retry
+	var transient *transientError
+	if errors.As(err, &transient) {
+		return errorKindFromStatus(transient.StatusCode)
+	}
+
+	return errorKindUnknown
+}
+
+// isNotFound reports whether the object does not exist in Passwork.
+func isNotFound(err error) bool {
+	return errorKindOf(err) == errorKindNotFound
+}
+
+// removeNotFound removes the resource from the state with a warning, if the object was deleted
+// outside of Terraform, so Terraform plans to create it again. It reports whether the resource
+// was removed. Other errors, e.g. missing permissions or network errors, are left to the caller.
+func removeNotFound(ctx context.Context, resp *resource.ReadResponse, kind, id string, err error) bool {
+	if !isNotFound(err) {
+		return false
+	}
+
+	tflog.Warn(ctx, "Removing "+kind+" from state, as it no longer exists in Passwork", map[string]interface{}{"id": id, "error": err.Error()})
+	resp.Diagnostics.AddWarning(
+		"Passwork "+kind+" Not Found",
+		"The "+kind+" "+id+" no longer exists in Passwork, e.g. as it was deleted outside of Terraform. "+
+			"It was removed from the state, so Terraform plans to create it again.\n\nError: "+err.Error(),
+	)
+	resp.State.RemoveResource(ctx)
+
+	return true
+}
+
+// ParseAPIError returns the summary and detail of the diagnostic for an error returned by the
+// Passwork API. action describes what failed, e.g. "create password" or "read vault abc".
+func ParseAPIError(err error, action string) (summary, detail string) {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "Passwork Operation Timed Out", fmt.Sprintf("Could not %s within the timeout of the operation. "+
+			"Increase the timeout in the timeouts block of the resource, or the timeout of the provider for single slow requests.\n\nError: %s", action, err.Error())
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return "Passwork Operation Cancelled", fmt.Sprintf("Could not %s, as the operation was cancelled, e.g. with Ctrl-C. "+
+			"Requests in progress were aborted. Run terraform plan to review the changes, which were made before.\n\nError: %s", action, err.Error())
+	}
+
+	switch errorKindOf(err) {
+	case errorKindAuth:
+		summary = "Passwork Authentication Failed"
+		detail = "Could not " + action + ", as Passwork rejected the credentials. " +
+			"Check that the api_key (or the PASSWORK_API_KEY environment variable) is valid and has not expired, and that host points to the right Passwork instance."
+	case errorKindForbidden:
+		summary = "Passwork Permission Denied"
+		detail = "Could not " + action + ", as the current user has no access to it. " +
+			"Make sure the user has the required access to the vault and folder, or ask a vault administrator to grant it."
+	case errorKindNotFound:
+		summary = "Passwork Object Not Found"
+		detail = "Could not " + action + ", as it does not exist in Passwork. " +
+			"Check the Id, or remove the object from the Terraform state, if it was deleted outside of Terraform."
+	case errorKindValidation:
+		summary = "Invalid Passwork Request"
+		detail = "Could not " + action + ", as Passwork rejected the values. " +
+			"Check the values in the configuration, e.g. for empty names or Ids of objects in another vault."
+	case errorKindConflict:
+		summary = "Passwork Conflict"
+		detail = "Could not " + action + ", as it conflicts with the current data in Passwork, e.g. an object with the same name or a concurrent change. " +
+			"Run terraform plan again to review the current data, or enable serialize_vault_writes to avoid concurrent changes within a vault."
+	case errorKindRateLimit:
+		summary = "Passwork Rate Limit Exceeded"
+		detail = "Could not " + action + ", as Passwork limited the rate of requests and all retries failed. " +
+			"Lower max_concurrent_requests or Terraform's -parallelism, or increase max_retries and retry_max_backoff."
+	case errorKindServer:
+		summary = "Passwork Server Error"
+		detail = "Could not " + action + ", as the Passwork server failed to process the request and all retries failed. " +
+			"Try again later or check the logs of the Passwork server."
+	default:
+		summary = "Unexpected Passwork Error"
+		detail = "Could not " + action + ", unexpected error."
+	}
+
+	return summary, fmt.Sprintf("%s\n\nError: %s", detail, err.Error())
+}
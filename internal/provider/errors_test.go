@@ -0,0 +1,168 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/lupa95/passwork-client-go"
+)
+
+func TestNewAPIError(t *testing.T) {
+	testCases := map[string]struct {
+		statusCode int
+		err        error
+		expected   errorKind
+	}{
+		"access denied":      {statusCode: http.StatusOK, err: errors.New("accessDenied"), expected: errorKindForbidden},
+		"password not found": {statusCode: http.StatusOK, err: errors.New("passwordNull"), expected: errorKindNotFound},
+		"folder not found":   {statusCode: http.StatusOK, err: errors.New("folderNotFound"), expected: errorKindNotFound},
+		"login failed":       {statusCode: http.StatusOK, err: errors.New("login failed, status: error"), expected: errorKindAuth},
+		"unauthorized":       {statusCode: http.StatusUnauthorized, err: errors.New("unauthorized"), expected: errorKindAuth},
+		"conflict":           {statusCode: http.StatusConflict, err: errors.New("unexpected response 409 Conflict"), expected: errorKindConflict},
+		"validation":         {statusCode: http.StatusBadRequest, err: errors.New("nameRequired"), expected: errorKindValidation},
+		"rate limit":         {statusCode: http.StatusTooManyRequests, err: fmt.Errorf("failed to parse JSON: %w", errors.New("invalid character")), expected: errorKindRateLimit},
+		"server error":       {statusCode: http.StatusBadGateway, err: fmt.Errorf("failed to parse JSON: %w", errors.New("invalid character")), expected: errorKindServer},
+		"bare 404":           {statusCode: http.StatusNotFound, err: fmt.Errorf("failed to parse JSON: %w", errors.New("invalid character")), expected: errorKindUnknown},
+		"unknown":            {statusCode: http.StatusOK, err: fmt.Errorf("failed to parse JSON: %w", errors.New("invalid character")), expected: errorKindUnknown},
+		"unknown code":       {statusCode: http.StatusOK, err: errors.New("customFieldNull"), expected: errorKindUnknown},
+		"expired session":    {statusCode: http.StatusOK, err: errors.New("expiredToken"), expected: errorKindAuth},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := newAPIError(testCase.statusCode, testCase.err)
+			if err.kind != testCase.expected {
+				t.Fatalf("expected kind %d, got %d", testCase.expected, err.kind)
+			}
+			if err.Error() != testCase.err.Error() {
+				t.Fatalf("expected the message to be kept, got %q", err.Error())
+			}
+		})
+	}
+}
+
+func TestV4BackendErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v4/passwords/missing":
+			fmt.Fprint(w, `{"status":"error","code":"passwordNull"}`)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `<html>Service Unavailable</html>`)
+		}
+	}))
+	defer server.Close()
+
+	backend := newV4Backend(passwork.NewClient(server.URL+"/api/v4", "test-key", 5*time.Second))
+	ctx := context.Background()
+
+	_, err := backend.GetPassword(ctx, "missing")
+	if !isNotFound(err) || err.Error() != "passwordNull" {
+		t.Fatalf("expected passwordNull not found error, got %v", err)
+	}
+
+	_, err = backend.GetPassword(ctx, "unavailable")
+	if errorKindOf(err) != errorKindServer {
+		t.Fatalf("expected server error, got %v", err)
+	}
+
+	retrying := newV4Backend(passwork.NewClient(server.URL+"/api/v4", "test-key", 5*time.Second))
+	retrying.client.HTTPClient.Transport = &retryTransport{base: http.DefaultTransport, policy: retryPolicy{maxRetries: 1}}
+	if _, err := retrying.GetPassword(ctx, "unavailable"); errorKindOf(err) != errorKindServer {
+		t.Fatalf("expected server error after the last retry, got %v", err)
+	}
+
+	summary, detail := ParseAPIError(err, "read password unavailable")
+	if summary != "Passwork Server Error" || !strings.HasPrefix(detail, "Could not read password unavailable, as the Passwork server failed") {
+		t.Fatalf("unexpected diagnostic %q: %s", summary, detail)
+	}
+}
+
+func TestParseAPIErrorTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+
+	deleted, err := (&passworkClient{}).deleteContents(ctx, contents{passwords: []contentPassword{{}}})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if !deleted.empty() {
+		t.Fatalf("expected nothing to be deleted, got %s", deleted.summary())
+	}
+
+	summary, detail := ParseAPIError(err, "delete the contents of vault abc")
+	if summary != "Passwork Operation Timed Out" || !strings.Contains(detail, "stopped after deleting 0 of 1 objects") {
+		t.Fatalf("unexpected diagnostic %q: %s", summary, detail)
+	}
+}
+
+func TestRemoveNotFound(t *testing.T) {
+	testCases := map[string]struct {
+		err     error
+		removed bool
+	}{
+		"not found":     {err: newAPIError(http.StatusOK, errors.New("vaultNotFound")), removed: true},
+		"http 404":      {err: newAPIError(http.StatusNotFound, fmt.Errorf("failed to parse JSON: %w", errors.New("invalid character")))},
+		"http 404 code": {err: newAPIError(http.StatusNotFound, errors.New("passwordNull")), removed: true},
+		"access denied": {err: newAPIError(http.StatusOK, errors.New("accessDenied"))},
+		"network":       {err: &url.Error{Op: "Get", URL: "https://passwork.example.com", Err: errors.New("connection refused")}},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			state := tfsdk.State{
+				Schema: schema.Schema{Attributes: map[string]schema.Attribute{"name": schema.StringAttribute{Required: true}}},
+				Raw:    testObject("vault"),
+			}
+			resp := resource.ReadResponse{State: state}
+			if removed := removeNotFound(context.Background(), &resp, "vault", "abc", testCase.err); removed != testCase.removed {
+				t.Fatalf("expected removed %t, got %t", testCase.removed, removed)
+			}
+			if resp.State.Raw.IsNull() != testCase.removed || resp.Diagnostics.WarningsCount() != len(resp.Diagnostics) {
+				t.Fatalf("unexpected state %v with diagnostics %v", resp.State.Raw, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestDeleteNotFound(t *testing.T) {
+	// The first attempt deleted the password, but its response was lost
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"error","code":"passwordNull"}`)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	r := &PasswordResource{client: newPassworkClient(newV4Backend(passwork.NewClient(server.URL+"/api/v4", "test-key", 5*time.Second)), false, "")}
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	values := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(attributeType, nil)
+	}
+	values["id"] = tftypes.NewValue(tftypes.String, "abc")
+	values["vault_id"] = tftypes.NewValue(tftypes.String, "vault")
+
+	req := resource.DeleteRequest{State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, values)}}
+	resp := resource.DeleteResponse{State: req.State}
+	r.Delete(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("expected deleting a missing password to succeed, got %v", resp.Diagnostics)
+	}
+}
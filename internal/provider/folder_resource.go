@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/lupa95/passwork-client-go"
 )
@@ -16,14 +19,19 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &FolderResource{}
 var _ resource.ResourceWithImportState = &FolderResource{}
+var _ resource.ResourceWithModifyPlan = &FolderResource{}
+var _ resource.ResourceWithConfigValidators = &FolderResource{}
 
 func NewFolderResource() resource.Resource {
 	return &FolderResource{}
 }
 
+// folderLocalAttributes are only stored in the Terraform state and never sent to Passwork.
+var folderLocalAttributes = []string{"deletion_protection", "recursive_delete", "timeouts"}
+
 // ExampleResource defines the resource implementation.
 type FolderResource struct {
-	client *passwork.Client
+	client *passworkClient
 }
 
 func (r *FolderResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -34,6 +42,18 @@ func (r *FolderResource) Schema(ctx context.Context, req resource.SchemaRequest,
 	resp.Schema = schema.Schema{
 		Description: "Use this resource to create a folder. Folders can be used to organize password entries. Folders need to be create inside a vault.",
 		Attributes: map[string]schema.Attribute{
+			"recursive_delete": schema.BoolAttribute{
+				Description: "Enable to delete all subfolders and passwords, which are still inside the folder, when it is destroyed. Otherwise a folder, which is not empty, is not deleted. Terraform only destroys managed subfolders and passwords before the folder, if their `parent_id` or `folder_id` references the folder. Objects, which use its Id as literal value or are managed in another state, are still inside it and are deleted with it. The value must be applied, before the folder is destroyed. Each deleted object is logged at info level, which is shown with `TF_LOG=info`, and a failed deletion lists the objects, which were already deleted. Defaults to `false`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"deletion_protection": schema.BoolAttribute{
+				Description: "Prevents the folder from being deleted, while set to `true`. Set it to `false` and apply the change, before the folder can be destroyed or removed from the configuration. Defaults to `false`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
 			"name": schema.StringAttribute{
 				Description: "The name of the folder entry.",
 				Required:    true,
@@ -41,6 +61,9 @@ func (r *FolderResource) Schema(ctx context.Context, req resource.SchemaRequest,
 			"vault_id": schema.StringAttribute{
 				Description: "The Id of the vault, which the folder should be created in.",
 				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
 			"id": schema.StringAttribute{
 				Description: "The Id of the folder.",
@@ -50,10 +73,22 @@ func (r *FolderResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				},
 			},
 			"parent_id": schema.StringAttribute{
-				Description: "The Id of the parent folder of the folder. Omit if this should be a top level folder.",
+				Description: "The Id of the parent folder of the folder. Omit if this should be a top level folder. The parent folder must belong to the vault given by `vault_id`, which is checked when planning.",
 				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx),
+		},
+	}
+}
+
+func (r *FolderResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		folderVaultValidator{folderAttribute: "parent_id"},
 	}
 }
 
@@ -63,11 +98,11 @@ func (r *FolderResource) Configure(ctx context.Context, req resource.ConfigureRe
 		return
 	}
 
-	client, ok := req.ProviderData.(*passwork.Client)
+	client, ok := req.ProviderData.(*passworkClient)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *passwork.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *passworkClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -77,6 +112,9 @@ func (r *FolderResource) Configure(ctx context.Context, req resource.ConfigureRe
 }
 
 func (r *FolderResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startSpan(ctx, "FolderResource.Create", "passwork_folder")
+	defer func() { endSpan(ctx, span, resp.Diagnostics) }()
+
 	var (
 		plan     FolderResourceModel
 		newState FolderResourceModel
@@ -92,6 +130,25 @@ func (r *FolderResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
+	setSpanAttributes(span, map[string]string{"vault_id": plan.VaultId.ValueString()})
+
+	// Bound the whole operation including retries and follow-up requests
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	// Serialize writes within the vault, if enabled
+	unlock, err := r.client.lockVault(ctx, plan.VaultId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(ParseAPIError(err, "lock vault "+plan.VaultId.ValueString()))
+		return
+	}
+	defer unlock()
+
 	// Build request
 	request.Name = plan.Name.ValueString()
 	if !plan.VaultId.IsNull() {
@@ -102,15 +159,26 @@ func (r *FolderResource) Create(ctx context.Context, req resource.CreateRequest,
 	}
 
 	// Send request
-	response, err = r.client.AddFolder(request)
+	response, err = r.client.AddFolder(ctx, request)
+	setSpanAttributes(span, map[string]string{"id": response.Data.Id})
 	if err != nil {
-		resp.Diagnostics.AddError(ParseFolderResponseError(err))
+		resp.Diagnostics.AddError(ParseAPIError(err, "create folder "+plan.Name.ValueString()))
+		// A retry can detect that the folder was created, although reading it failed
+		if response.Data.Id != "" {
+			savePartialState(ctx, req.Plan, resp, "folder", response.Data.Id, nil)
+		}
 		return
 	}
 
 	// Convert response to model
 	newState = FolderResponseToModel(response)
 
+	// deletion_protection is not stored in Passwork
+	newState.DeletionProtection = plan.DeletionProtection
+	newState.RecursiveDelete = plan.RecursiveDelete
+
+	newState.Timeouts = plan.Timeouts
+
 	// Set refreshed state
 	diags = resp.State.Set(ctx, newState)
 	resp.Diagnostics.Append(diags...)
@@ -120,6 +188,9 @@ func (r *FolderResource) Create(ctx context.Context, req resource.CreateRequest,
 }
 
 func (r *FolderResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startSpan(ctx, "FolderResource.Read", "passwork_folder")
+	defer func() { endSpan(ctx, span, resp.Diagnostics) }()
+
 	var (
 		state    FolderResourceModel
 		newState FolderResourceModel
@@ -134,15 +205,42 @@ func (r *FolderResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
-	response, err = r.client.GetFolder(state.Id.ValueString())
+	setSpanAttributes(span, map[string]string{"id": state.Id.ValueString(), "vault_id": state.VaultId.ValueString()})
+
+	// Bound the whole operation including retries and follow-up requests
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	response, err = r.client.GetFolder(ctx, state.Id.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError(ParseFolderResponseError(err))
+		// Remove resource from state, if it was deleted outside of Terraform
+		if removeNotFound(ctx, resp, "folder", state.Id.ValueString(), err) {
+			return
+		}
+		resp.Diagnostics.AddError(ParseAPIError(err, "read folder "+state.Id.ValueString()))
+		return
+	}
+
+	// The vault of the folder is only known from the API, e.g. after an import
+	addVaultError(&resp.Diagnostics, path.Root("vault_id"), r.client.checkVault(ctx, response.Data.VaultId))
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	// Convert response to model
 	newState = FolderResponseToModel(response)
 
+	// deletion_protection is not stored in Passwork and defaults to false for imported folders
+	newState.DeletionProtection = localBoolState(state.DeletionProtection)
+	newState.RecursiveDelete = localBoolState(state.RecursiveDelete)
+
+	newState.Timeouts = state.Timeouts
+
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &newState)
 	resp.Diagnostics.Append(diags...)
@@ -152,6 +250,9 @@ func (r *FolderResource) Read(ctx context.Context, req resource.ReadRequest, res
 }
 
 func (r *FolderResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startSpan(ctx, "FolderResource.Update", "passwork_folder")
+	defer func() { endSpan(ctx, span, resp.Diagnostics) }()
+
 	var (
 		plan     FolderResourceModel
 		newState FolderResourceModel
@@ -167,19 +268,61 @@ func (r *FolderResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	setSpanAttributes(span, map[string]string{"id": plan.Id.ValueString(), "vault_id": plan.VaultId.ValueString()})
+
+	// Changes of attributes, which are only stored in the state, are not sent to Passwork
+	if !remoteAttributesChanged(req.Plan.Raw, req.State.Raw, folderLocalAttributes...) {
+		var state FolderResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		state.DeletionProtection = plan.DeletionProtection
+		state.RecursiveDelete = plan.RecursiveDelete
+		state.Timeouts = plan.Timeouts
+		resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+		return
+	}
+
+	// Bound the whole operation including retries and follow-up requests
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	// Serialize writes within the vault, if enabled
+	unlock, err := r.client.lockVault(ctx, plan.VaultId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(ParseAPIError(err, "lock vault "+plan.VaultId.ValueString()))
+		return
+	}
+	defer unlock()
+
 	// Build request
 	request.Name = plan.Name.ValueString()
+	if !plan.ParentId.IsNull() {
+		request.ParentId = plan.ParentId.ValueString()
+	}
 
 	// Send request
-	response, err = r.client.EditFolder(plan.Id.ValueString(), request)
+	response, err = r.client.EditFolder(ctx, plan.Id.ValueString(), request)
 	if err != nil {
-		resp.Diagnostics.AddError(ParseFolderResponseError(err))
+		resp.Diagnostics.AddError(ParseAPIError(err, "update folder "+plan.Id.ValueString()))
 		return
 	}
 
 	// Convert response to model
 	newState = FolderResponseToModel(response)
 
+	// deletion_protection is not stored in Passwork
+	newState.DeletionProtection = plan.DeletionProtection
+	newState.RecursiveDelete = plan.RecursiveDelete
+
+	newState.Timeouts = plan.Timeouts
+
 	// Set refreshed state
 	diags = resp.State.Set(ctx, newState)
 	resp.Diagnostics.Append(diags...)
@@ -190,6 +333,9 @@ func (r *FolderResource) Update(ctx context.Context, req resource.UpdateRequest,
 }
 
 func (r *FolderResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startSpan(ctx, "FolderResource.Delete", "passwork_folder")
+	defer func() { endSpan(ctx, span, resp.Diagnostics) }()
+
 	var (
 		plan FolderResourceModel
 		err  error
@@ -202,14 +348,84 @@ func (r *FolderResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	// Send request
-	_, err = r.client.DeleteFolder(plan.Id.ValueString())
+	setSpanAttributes(span, map[string]string{"id": plan.Id.ValueString(), "vault_id": plan.VaultId.ValueString()})
+
+	// Bound the whole operation including retries and follow-up requests
+	deleteTimeout, diags := plan.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	if plan.DeletionProtection.ValueBool() {
+		addDeletionProtectionError(&resp.Diagnostics, "Folder", plan.Id.ValueString())
+		return
+	}
+
+	// Serialize writes within the vault, if enabled
+	unlock, err := r.client.lockVault(ctx, plan.VaultId.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError(ParseFolderResponseError(err))
+		resp.Diagnostics.AddError(ParseAPIError(err, "lock vault "+plan.VaultId.ValueString()))
+		return
+	}
+	defer unlock()
+
+	// Managed passwords and subfolders referencing the folder are destroyed before it
+	contents, err := r.client.listContents(ctx, plan.VaultId.ValueString(), plan.Id.ValueString())
+	if isNotFound(err) {
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(ParseAPIError(err, "list the subfolders and passwords of folder "+plan.Id.ValueString()))
+		return
+	}
+
+	if !contents.empty() {
+		if !plan.RecursiveDelete.ValueBool() {
+			resp.Diagnostics.AddError(folderNotEmptyError(plan.Id.ValueString(), contents))
+			return
+		}
+
+		deleted, err := r.client.deleteContents(ctx, contents)
+		if err != nil {
+			summary, detail := ParseAPIError(err, "delete the contents of folder "+plan.Id.ValueString())
+			resp.Diagnostics.AddError(summary, detail+describeDeleted(deleted))
+			return
+		}
+		resp.Diagnostics.AddWarning(
+			"Deleted Passwork Folder Contents",
+			"The folder "+plan.Id.ValueString()+" was deleted with recursive_delete including "+contents.summary()+":\n\n"+contents.describe(),
+		)
+	}
+
+	// Send request
+	_, err = r.client.DeleteFolder(ctx, plan.Id.ValueString())
+	// A retried delete finds the folder already deleted by the first attempt
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError(ParseAPIError(err, "delete folder "+plan.Id.ValueString()))
 		return
 	}
 }
 
+// folderNotEmptyError returns the diagnostic for a folder, which is not deleted, as it still contains objects.
+// They are not necessarily unmanaged, Terraform only destroys managed objects first, if they reference the folder.
+func folderNotEmptyError(folderId string, contents contents) (summary, detail string) {
+	return "Passwork Folder Not Empty",
+		"The folder " + folderId + " is not deleted, as it still contains " + contents.summary() + ":\n\n" + contents.describe() + "\n\n" +
+			"Terraform only destroys subfolders and passwords of the configuration before the folder, if their parent_id or folder_id references it, e.g. passwork_folder.example.id. " +
+			"The objects above were either created outside of Terraform, are managed in another state, or use the Id of the folder as literal value. " +
+			"Move or delete them first, reference the folder in their configuration, or set recursive_delete = true in the configuration and apply the change to delete the folder with its contents."
+}
+
+func (r *FolderResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkReadOnly(r.client, req, resp, "passwork_folder")
+	checkPlannedVault(ctx, r.client, req, resp, "vault_id")
+	checkPlannedFolderVault(ctx, r.client, req, resp, "parent_id")
+	checkPlannedPermissions(ctx, r.client, req, resp, "parent_id", folderLocalAttributes...)
+}
+
 func (r *FolderResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
@@ -228,11 +444,3 @@ func FolderResponseToModel(response passwork.FolderResponse) FolderResourceModel
 
 	return folder
 }
-
-func ParseFolderResponseError(err error) (summary, detail string) {
-	if err.Error() == "accessDenied" {
-		return "Folder permission error.", "Could not create, update or read folder. Make sure you have access to the folder and vault."
-	}
-
-	return "Unexpected error", "Could not create, update or read folder. Error: " + err.Error()
-}
@@ -22,6 +22,8 @@ func TestFolderResource(t *testing.T) {
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("passwork_folder.test", "name", folderName),
 					resource.TestCheckResourceAttrSet("passwork_folder.test", "id"),
+					resource.TestCheckResourceAttr("passwork_folder.test", "deletion_protection", "false"),
+					resource.TestCheckResourceAttr("passwork_folder.test", "recursive_delete", "false"),
 					resource.TestCheckResourceAttrPair("passwork_folder.test_nested", "parent_id", "passwork_folder.test", "id"),
 				),
 			},
@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// folderVaultError is returned, if a folder does not belong to the vault it is used with.
+type folderVaultError struct {
+	folderId   string
+	folderName string
+	vaultId    string
+	// actualVaultId and actualVaultName describe the vault the folder belongs to.
+	actualVaultId   string
+	actualVaultName string
+}
+
+func (e *folderVaultError) Error() string {
+	actual := e.actualVaultId
+	if e.actualVaultName != "" {
+		actual = fmt.Sprintf("%q (%s)", e.actualVaultName, e.actualVaultId)
+	}
+
+	return fmt.Sprintf("folder %q (%s) belongs to vault %s, not to vault %s. Set vault_id to %s or use a folder of vault %s.", e.folderName, e.folderId, actual, e.vaultId, e.actualVaultId, e.vaultId)
+}
+
+// checkFolderVault returns a folderVaultError, if the folder does not belong to the vault.
+func (c *passworkClient) checkFolderVault(ctx context.Context, folderId, vaultId string) error {
+	folder, err := c.cachedFolder(ctx, folderId)
+	if err != nil {
+		return err
+	}
+
+	if folder.VaultId == vaultId {
+		return nil
+	}
+
+	// The name only makes the error easier to understand, so it is not required
+	vaultName, _ := c.vaultName(ctx, folder.VaultId)
+
+	return &folderVaultError{
+		folderId:        folderId,
+		folderName:      folder.Name,
+		vaultId:         vaultId,
+		actualVaultId:   folder.VaultId,
+		actualVaultName: vaultName,
+	}
+}
+
+var _ resource.ConfigValidator = folderVaultValidator{}
+
+// folderVaultValidator validates the folder attribute against vault_id without API requests, as the
+// provider is not configured yet, when the configuration is validated. Whether the folder belongs to
+// the vault is checked with the API when planning.
+type folderVaultValidator struct {
+	folderAttribute string
+}
+
+func (v folderVaultValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("%s must be the Id of a folder in the vault, not the Id of the vault", v.folderAttribute)
+}
+
+func (v folderVaultValidator) MarkdownDescription(ctx context.Context) string {
+	return fmt.Sprintf("`%s` must be the Id of a folder in the vault, not the Id of the vault", v.folderAttribute)
+}
+
+func (v folderVaultValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var vaultId, folderId types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("vault_id"), &vaultId)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(v.folderAttribute), &folderId)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Values referencing other resources are only known when planning
+	if vaultId.IsNull() || vaultId.IsUnknown() || folderId.IsNull() || folderId.IsUnknown() {
+		return
+	}
+
+	if folderId.ValueString() == vaultId.ValueString() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root(v.folderAttribute),
+			"Invalid Folder",
+			fmt.Sprintf("%s is set to the Id of the vault %s instead of a folder in it. Omit %s to use the top level of the vault.", v.folderAttribute, vaultId.ValueString(), v.folderAttribute),
+		)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/lupa95/passwork-client-go"
+)
+
+func TestCheckFolderVault(t *testing.T) {
+	responses := map[string]string{
+		"/api/v4/folders/folder": `{"id":"folder","name":"Databases","vaultId":"team"}`,
+		"/api/v4/vaults/team":    `{"id":"team","name":"Team"}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := responses[r.URL.Path]
+		if !ok {
+			fmt.Fprint(w, `{"status":"error","code":"folderNotFound"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"status":"success","data":%s}`, data)
+	}))
+	defer server.Close()
+
+	client := newPassworkClient(newV4Backend(passwork.NewClient(server.URL+"/api/v4", "test-key", 5*time.Second)), false, "")
+	ctx := context.Background()
+
+	if err := client.checkFolderVault(ctx, "folder", "team"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var mismatch *folderVaultError
+	err := client.checkFolderVault(ctx, "folder", "other")
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("expected folderVaultError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `belongs to vault "Team" (team), not to vault other`) {
+		t.Fatalf("expected the actual vault in the error, got: %s", err)
+	}
+
+	if err := client.checkFolderVault(ctx, "missing", "team"); err == nil || err.Error() != "folderNotFound" {
+		t.Fatalf("expected folderNotFound error, got %v", err)
+	}
+}
+
+func TestFolderVaultValidator(t *testing.T) {
+	ctx := context.Background()
+
+	var schemaResp resource.SchemaResponse
+	(&PasswordResource{}).Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	testCases := map[string]struct {
+		folderId    tftypes.Value
+		expectError bool
+	}{
+		"folder":      {folderId: tftypes.NewValue(tftypes.String, "folder")},
+		"top level":   {folderId: tftypes.NewValue(tftypes.String, nil)},
+		"unknown":     {folderId: tftypes.NewValue(tftypes.String, tftypes.UnknownValue)},
+		"vault as id": {folderId: tftypes.NewValue(tftypes.String, "vault"), expectError: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			values := map[string]tftypes.Value{}
+			for name, attributeType := range objectType.AttributeTypes {
+				values[name] = tftypes.NewValue(attributeType, nil)
+			}
+			values["vault_id"] = tftypes.NewValue(tftypes.String, "vault")
+			values["folder_id"] = testCase.folderId
+
+			req := resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, values)}}
+			var resp resource.ValidateConfigResponse
+			folderVaultValidator{folderAttribute: "folder_id"}.ValidateResource(ctx, req, &resp)
+			if resp.Diagnostics.HasError() != testCase.expectError {
+				t.Fatalf("expected error %t, got diagnostics %v", testCase.expectError, resp.Diagnostics)
+			}
+		})
+	}
+}
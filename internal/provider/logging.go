@@ -0,0 +1,120 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// logSubsystem is the tflog subsystem for Passwork API calls. Its level can be set
+// separately with the TF_LOG_PROVIDER_PASSWORK_API environment variable.
+const logSubsystem = "passwork_api"
+
+// secretFieldsRegex matches JSON fields of request and response bodies, which contain
+// secrets. Custom field values are stored in "value" fields.
+var secretFieldsRegex = regexp.MustCompile(`(?i)("(?:token|accessToken|refreshToken|cryptedPassword|cryptedKey|mpCrypted|passwordCrypted|vaultPasswordCrypted|passwordHash|masterHash|salt|password|value)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// newLoggingContext creates the logging subsystem for API calls. All given secrets, e.g.
+// the API key which is part of the login URL, are masked in messages and fields.
+func newLoggingContext(ctx context.Context, secrets ...string) context.Context {
+	ctx = tflog.NewSubsystem(ctx, logSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER_PASSWORK_API"))
+
+	var maskedSecrets []string
+	for _, secret := range secrets {
+		if secret != "" {
+			maskedSecrets = append(maskedSecrets, secret)
+		}
+	}
+
+	return tflog.SubsystemMaskLogStrings(ctx, logSubsystem, maskedSecrets...)
+}
+
+// redactBody masks the values of secret fields in a JSON body.
+func redactBody(body []byte) string {
+	return secretFieldsRegex.ReplaceAllString(string(body), `$1"***"`)
+}
+
+// loggingTransport logs every API call with method, path, status, duration and a request ID.
+// Request and response bodies are logged with secrets redacted at TRACE level. The logger is
+// taken from the context of each request, which carries the logger of the Terraform operation.
+type loggingTransport struct {
+	base    http.RoundTripper
+	secrets []string
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := newLoggingContext(req.Context(), t.secrets...)
+	requestId := req.Header.Get("X-Request-Id")
+	if requestId == "" {
+		requestId = newRequestId()
+		req = req.Clone(req.Context())
+		req.Header.Set("X-Request-Id", requestId)
+	}
+
+	fields := map[string]interface{}{
+		"method":     req.Method,
+		"path":       req.URL.Path,
+		"request_id": requestId,
+	}
+
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, err := io.ReadAll(body)
+			body.Close()
+			if err == nil && len(data) > 0 {
+				tflog.SubsystemTrace(ctx, logSubsystem, "Passwork API request body", mergeFields(fields, map[string]interface{}{
+					"body": redactBody(data),
+				}))
+			}
+		}
+	}
+
+	tflog.SubsystemDebug(ctx, logSubsystem, "Sending Passwork API request", fields)
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	fields["duration_ms"] = time.Since(start).Milliseconds()
+
+	if err != nil {
+		tflog.SubsystemError(ctx, logSubsystem, "Passwork API request failed", mergeFields(fields, map[string]interface{}{
+			"error": err.Error(),
+		}))
+		return nil, err
+	}
+
+	fields["status"] = resp.StatusCode
+	tflog.SubsystemDebug(ctx, logSubsystem, "Received Passwork API response", fields)
+
+	if body, err := readBody(resp); err == nil && len(body) > 0 {
+		tflog.SubsystemTrace(ctx, logSubsystem, "Passwork API response body", mergeFields(fields, map[string]interface{}{
+			"body": redactBody(body),
+		}))
+	}
+
+	return resp, nil
+}
+
+func newRequestId() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+func mergeFields(fields ...map[string]interface{}) map[string]interface{} {
+	merged := map[string]interface{}{}
+	for _, f := range fields {
+		for key, value := range f {
+			merged[key] = value
+		}
+	}
+	return merged
+}
@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestRedactBody(t *testing.T) {
+	body := `{"status":"success","data":{"token":"session-token","name":"test","cryptedPassword":"c2VjcmV0","mpCrypted" : "bXA=",` +
+		`"custom":[{"name":"pin","value":"1234","type":"password"}],"description":"escaped \"quote\""}}`
+
+	redacted := redactBody([]byte(body))
+
+	for _, secret := range []string{"session-token", "c2VjcmV0", "bXA=", "1234"} {
+		if strings.Contains(redacted, secret) {
+			t.Errorf("expected %q to be redacted: %s", secret, redacted)
+		}
+	}
+
+	for _, value := range []string{`"name":"test"`, `"name":"pin"`, `"token":"***"`, `"description":"escaped \"quote\""`} {
+		if !strings.Contains(redacted, value) {
+			t.Errorf("expected %q to be kept: %s", value, redacted)
+		}
+	}
+}
+
+func TestLoggingTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"success","data":{"token":"session-token"}}`)
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	client := &http.Client{Transport: &loggingTransport{base: http.DefaultTransport, secrets: []string{"test-key"}}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/api/v4/auth/login/test-key", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp.Body.Close()
+
+	logs := output.String()
+	for _, expected := range []string{"Received Passwork API response", `"status":200`, "/api/v4/auth/login/***"} {
+		if !strings.Contains(logs, expected) {
+			t.Errorf("expected the logs of the request context to contain %q:\n%s", expected, logs)
+		}
+	}
+	for _, secret := range []string{"test-key", "session-token"} {
+		if strings.Contains(logs, secret) {
+			t.Errorf("expected %q to be masked:\n%s", secret, logs)
+		}
+	}
+}
@@ -1,8 +1,32 @@
 package provider
 
-import "github.com/hashicorp/terraform-plugin-framework/types"
+import (
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
 
 type PasswordResourceModel struct {
+	VaultId     types.String `tfsdk:"vault_id"`
+	FolderId    types.String `tfsdk:"folder_id"`
+	Id          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Login       types.String `tfsdk:"login"`
+	Password    types.String `tfsdk:"password"`
+	Description types.String `tfsdk:"description"`
+	Url         types.String `tfsdk:"url"`
+	Color       types.Int32  `tfsdk:"color"`
+	Tags        tagsValue    `tfsdk:"tags"`
+	Access      types.String `tfsdk:"access"`
+	AccessCode  types.Int32  `tfsdk:"access_code"`
+
+	DeletionProtection     types.Bool `tfsdk:"deletion_protection"`
+	OverwriteRemoteChanges types.Bool `tfsdk:"overwrite_remote_changes"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// passwordResourceModelV0 is the state of version 0 of the password resource, which stored the tags as list.
+type passwordResourceModelV0 struct {
 	VaultId     types.String   `tfsdk:"vault_id"`
 	FolderId    types.String   `tfsdk:"folder_id"`
 	Id          types.String   `tfsdk:"id"`
@@ -15,6 +39,11 @@ type PasswordResourceModel struct {
 	Tags        []types.String `tfsdk:"tags"`
 	Access      types.String   `tfsdk:"access"`
 	AccessCode  types.Int32    `tfsdk:"access_code"`
+
+	DeletionProtection     types.Bool `tfsdk:"deletion_protection"`
+	OverwriteRemoteChanges types.Bool `tfsdk:"overwrite_remote_changes"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
 type passwordDataSourceModel struct {
@@ -25,7 +54,7 @@ type passwordDataSourceModel struct {
 	Description types.String `tfsdk:"description"`
 	Login       types.String `tfsdk:"login"`
 	Url         types.String `tfsdk:"url"`
-	Tags        types.List   `tfsdk:"tags"`
+	Tags        types.Set    `tfsdk:"tags"`
 	Access      types.String `tfsdk:"access"`
 	AccessCode  types.Int32  `tfsdk:"access_code"`
 }
@@ -37,6 +66,11 @@ type VaultResourceModel struct {
 	Access         types.String `tfsdk:"access"`
 	Scope          types.String `tfsdk:"scope"`
 	IsPrivate      types.Bool   `tfsdk:"is_private"`
+
+	DeletionProtection types.Bool `tfsdk:"deletion_protection"`
+	ForceDestroy       types.Bool `tfsdk:"force_destroy"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
 type FolderResourceModel struct {
@@ -44,4 +78,9 @@ type FolderResourceModel struct {
 	VaultId  types.String `tfsdk:"vault_id"`
 	Id       types.String `tfsdk:"id"`
 	ParentId types.String `tfsdk:"parent_id"`
+
+	DeletionProtection types.Bool `tfsdk:"deletion_protection"`
+	RecursiveDelete    types.Bool `tfsdk:"recursive_delete"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
@@ -0,0 +1,233 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Actions Terraform can plan for a resource.
+const (
+	planActionNone   = ""
+	planActionCreate = "create"
+	planActionUpdate = "update"
+	planActionDelete = "delete"
+)
+
+// planAction returns the action Terraform plans for the resource.
+func planAction(req resource.ModifyPlanRequest) string {
+	switch {
+	case req.Plan.Raw.IsNull():
+		return planActionDelete
+	case req.State.Raw.IsNull():
+		return planActionCreate
+	case !req.Plan.Raw.Equal(req.State.Raw):
+		return planActionUpdate
+	}
+
+	return planActionNone
+}
+
+// checkReadOnly fails the plan, if the provider is read-only and the resource would be changed.
+func checkReadOnly(client *passworkClient, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, resourceType string) {
+	// The client is not configured yet, if the provider configuration is unknown
+	if client == nil || !client.readOnly {
+		return
+	}
+
+	action := planAction(req)
+	if action == planActionNone {
+		return
+	}
+
+	resp.Diagnostics.AddError(
+		"Passwork Provider Is Read-Only",
+		fmt.Sprintf("Cannot %s %s, as the provider is configured as read-only with the read_only attribute or the PASSWORK_READ_ONLY environment variable. ", action, resourceType)+
+			"Data sources can still be used. Either remove the resource from the configuration or disable the read-only mode.",
+	)
+}
+
+// checkPlannedVault fails the plan, if the resource would be changed in a vault, which is not allowed.
+// vaultAttribute is the attribute containing the vault Id, i.e. vault_id or id for vaults.
+func checkPlannedVault(ctx context.Context, client *passworkClient, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, vaultAttribute string) {
+	if client == nil || !client.vaultFilter.enabled() || planAction(req) == planActionNone {
+		return
+	}
+
+	var stateVaultId, planVaultId types.String
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root(vaultAttribute), &stateVaultId)...)
+	}
+	if !req.Plan.Raw.IsNull() {
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root(vaultAttribute), &planVaultId)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Check the current vault and the vault the resource is moved to
+	if planVaultId.Equal(stateVaultId) {
+		planVaultId = types.StringNull()
+	}
+	for _, vaultId := range []types.String{stateVaultId, planVaultId} {
+		if vaultId.IsNull() || vaultId.IsUnknown() {
+			continue
+		}
+
+		addVaultError(&resp.Diagnostics, path.Root(vaultAttribute), client.checkVault(ctx, vaultId.ValueString()))
+	}
+}
+
+// checkPlannedFolderVault fails the plan, if the folder the resource is created or moved in
+// does not belong to the planned vault. folderAttribute is the attribute containing the Id
+// of the folder, i.e. folder_id for passwords or parent_id for folders.
+func checkPlannedFolderVault(ctx context.Context, client *passworkClient, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, folderAttribute string) {
+	if client == nil || resp.Diagnostics.HasError() || req.Plan.Raw.IsNull() || planAction(req) == planActionNone {
+		return
+	}
+
+	var vaultId, folderId types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("vault_id"), &vaultId)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root(folderAttribute), &folderId)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Ids of vaults and folders created in the same apply are checked, once they are known
+	if vaultId.IsUnknown() || folderId.IsNull() || folderId.IsUnknown() {
+		return
+	}
+
+	err := client.checkFolderVault(ctx, folderId.ValueString(), vaultId.ValueString())
+	var mismatch *folderVaultError
+	switch {
+	case err == nil:
+		return
+	case errors.As(err, &mismatch):
+		resp.Diagnostics.AddAttributeError(path.Root(folderAttribute), "Passwork Folder In Different Vault", "The "+err.Error())
+	default:
+		summary, detail := ParseAPIError(err, "read folder "+folderId.ValueString()+" to check its vault")
+		resp.Diagnostics.AddAttributeError(path.Root(folderAttribute), summary, detail)
+	}
+}
+
+// remoteChangePlanned reports whether the resource is created or an attribute stored in Passwork
+// is changed. localAttributes are only stored in the Terraform state, e.g. deletion_protection.
+func remoteChangePlanned(req resource.ModifyPlanRequest, localAttributes ...string) bool {
+	switch planAction(req) {
+	case planActionCreate:
+		return true
+	case planActionUpdate:
+		return remoteAttributesChanged(req.Plan.Raw, req.State.Raw, localAttributes...)
+	}
+
+	return false
+}
+
+// remoteAttributesChanged reports whether the planned and prior values differ in an attribute other
+// than localAttributes. Update only sends a request to Passwork, if this is the case.
+func remoteAttributesChanged(plan, state tftypes.Value, localAttributes ...string) bool {
+	local := map[string]bool{}
+	for _, attribute := range localAttributes {
+		local[attribute] = true
+	}
+	withoutLocal := func(value tftypes.Value) tftypes.Value {
+		result, err := tftypes.Transform(value, func(attributePath *tftypes.AttributePath, value tftypes.Value) (tftypes.Value, error) {
+			if steps := attributePath.Steps(); len(steps) == 1 {
+				if name, ok := steps[0].(tftypes.AttributeName); ok && local[string(name)] {
+					return tftypes.NewValue(value.Type(), nil), nil
+				}
+			}
+			return value, nil
+		})
+		if err != nil {
+			return value
+		}
+		return result
+	}
+
+	return !withoutLocal(plan).Equal(withoutLocal(state))
+}
+
+// checkPlannedPermissions fails the plan, if the current user may not write to the vault or folder
+// the resource is created or changed in. Deletes and changes of localAttributes, which are not sent
+// to Passwork, are not checked. folderAttribute is the attribute containing the Id of the folder,
+// i.e. folder_id for passwords or parent_id for folders.
+func checkPlannedPermissions(ctx context.Context, client *passworkClient, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, folderAttribute string, localAttributes ...string) {
+	if client == nil || resp.Diagnostics.HasError() || !remoteChangePlanned(req, localAttributes...) {
+		return
+	}
+
+	checks := []struct {
+		attribute string
+		check     func(context.Context, string) error
+	}{
+		{"vault_id", client.checkVaultWriteAccess},
+		{folderAttribute, client.checkFolderWriteAccess},
+	}
+
+	for _, c := range checks {
+		var stateId, planId types.String
+		if !req.State.Raw.IsNull() {
+			resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root(c.attribute), &stateId)...)
+		}
+		if !req.Plan.Raw.IsNull() {
+			resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root(c.attribute), &planId)...)
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		// Check the current vault or folder and the one the resource is moved to
+		if planId.Equal(stateId) {
+			planId = types.StringNull()
+		}
+		for _, id := range []types.String{stateId, planId} {
+			if id.IsNull() || id.IsUnknown() || id.ValueString() == "" {
+				continue
+			}
+
+			// A vault or folder deleted outside of Terraform is reported when refreshing or applying
+			if err := c.check(ctx, id.ValueString()); !isNotFound(err) {
+				addPermissionError(&resp.Diagnostics, path.Root(c.attribute), err)
+			}
+		}
+	}
+}
+
+// addPermissionError adds a diagnostic for an error returned by checking the access level.
+func addPermissionError(diags *diag.Diagnostics, attributePath path.Path, err error) {
+	var permission *permissionError
+	switch {
+	case err == nil:
+		return
+	case errors.As(err, &permission):
+		diags.AddAttributeError(attributePath, "Missing Passwork Write Permission", "Cannot plan the change, as "+err.Error())
+	default:
+		summary, detail := ParseAPIError(err, "read the access level of the current user")
+		diags.AddAttributeError(attributePath, summary, detail)
+	}
+}
+
+// addVaultError adds a diagnostic for an error returned by checking the vault.
+func addVaultError(diags *diag.Diagnostics, attributePath path.Path, err error) {
+	var notAllowed *vaultNotAllowedError
+	switch {
+	case err == nil:
+		return
+	case errors.As(err, &notAllowed):
+		diags.AddAttributeError(attributePath, "Passwork Vault Not Allowed", err.Error())
+	default:
+		summary, detail := ParseAPIError(err, "read the vault to check if it is allowed")
+		diags.AddAttributeError(attributePath, summary, detail)
+	}
+}
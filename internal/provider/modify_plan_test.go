@@ -0,0 +1,207 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func testObject(name string) tftypes.Value {
+	objectType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"name": tftypes.String}}
+	if name == "" {
+		return tftypes.NewValue(objectType, nil)
+	}
+	return tftypes.NewValue(objectType, map[string]tftypes.Value{"name": tftypes.NewValue(tftypes.String, name)})
+}
+
+func testModifyPlanRequest(state, plan string) resource.ModifyPlanRequest {
+	return resource.ModifyPlanRequest{
+		State: tfsdk.State{Raw: testObject(state)},
+		Plan:  tfsdk.Plan{Raw: testObject(plan)},
+	}
+}
+
+func TestCheckReadOnly(t *testing.T) {
+	testCases := map[string]struct {
+		req      resource.ModifyPlanRequest
+		action   string
+		readOnly bool
+	}{
+		"create":    {req: testModifyPlanRequest("", "new"), action: planActionCreate, readOnly: true},
+		"update":    {req: testModifyPlanRequest("old", "new"), action: planActionUpdate, readOnly: true},
+		"delete":    {req: testModifyPlanRequest("old", ""), action: planActionDelete, readOnly: true},
+		"no change": {req: testModifyPlanRequest("old", "old"), action: planActionNone, readOnly: true},
+		"writable":  {req: testModifyPlanRequest("", "new"), action: planActionCreate},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if action := planAction(testCase.req); action != testCase.action {
+				t.Fatalf("expected action %q, got %q", testCase.action, action)
+			}
+
+			client := newPassworkClient(nil, false, "")
+			client.readOnly = testCase.readOnly
+
+			var resp resource.ModifyPlanResponse
+			checkReadOnly(client, testCase.req, &resp, "passwork_password")
+
+			expectError := testCase.readOnly && testCase.action != planActionNone
+			if resp.Diagnostics.HasError() != expectError {
+				t.Fatalf("expected error %t, got diagnostics %v", expectError, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestCheckPlannedVaultPassword(t *testing.T) {
+	ctx := context.Background()
+
+	var schemaResp resource.SchemaResponse
+	(&VaultResource{}).Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	vault := func(masterPassword tftypes.Value) tftypes.Value {
+		values := map[string]tftypes.Value{}
+		for name, attributeType := range objectType.AttributeTypes {
+			values[name] = tftypes.NewValue(attributeType, nil)
+		}
+		values["name"] = tftypes.NewValue(tftypes.String, "vault")
+		values["master_password"] = masterPassword
+		return tftypes.NewValue(objectType, values)
+	}
+
+	testCases := map[string]struct {
+		config      tftypes.Value
+		plan        tftypes.Value
+		expectError bool
+	}{
+		"generated":  {config: vault(tftypes.NewValue(tftypes.String, nil)), plan: vault(tftypes.NewValue(tftypes.String, tftypes.UnknownValue))},
+		"configured": {config: vault(tftypes.NewValue(tftypes.String, "secret")), plan: vault(tftypes.NewValue(tftypes.String, "secret")), expectError: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			client := newPassworkClient(&v7Backend{}, false, "")
+			req := resource.ModifyPlanRequest{
+				Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: testCase.config},
+				Plan:   tfsdk.Plan{Schema: schemaResp.Schema, Raw: testCase.plan},
+			}
+			resp := resource.ModifyPlanResponse{Plan: req.Plan}
+
+			checkPlannedVaultPassword(ctx, client, req, &resp)
+			if resp.Diagnostics.HasError() != testCase.expectError {
+				t.Fatalf("expected error %t, got diagnostics %v", testCase.expectError, resp.Diagnostics)
+			}
+			if testCase.expectError {
+				return
+			}
+
+			var plan VaultResourceModel
+			resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+			if !plan.MasterPassword.IsNull() {
+				t.Fatalf("expected the master password to be planned as null, got %s", plan.MasterPassword)
+			}
+		})
+	}
+}
+
+func TestCheckPlannedVaultEncryption(t *testing.T) {
+	testCases := map[string]struct {
+		clientSideEncryption bool
+		state                tftypes.Value
+		expectError          bool
+	}{
+		"create":             {clientSideEncryption: true, state: testObject(""), expectError: true},
+		"update":             {clientSideEncryption: true, state: testObject("old")},
+		"without encryption": {state: testObject("")},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req := resource.ModifyPlanRequest{State: tfsdk.State{Raw: testCase.state}, Plan: tfsdk.Plan{Raw: testObject("vault")}}
+			resp := resource.ModifyPlanResponse{}
+
+			checkPlannedVaultEncryption(newPassworkClient(nil, testCase.clientSideEncryption, ""), req, &resp)
+			if resp.Diagnostics.HasError() != testCase.expectError {
+				t.Fatalf("expected error %t, got diagnostics %v", testCase.expectError, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestRemoteChangePlanned(t *testing.T) {
+	objectType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"name": tftypes.String, "deletion_protection": tftypes.Bool}}
+	object := func(name string, deletionProtection bool) tftypes.Value {
+		return tftypes.NewValue(objectType, map[string]tftypes.Value{
+			"name":                tftypes.NewValue(tftypes.String, name),
+			"deletion_protection": tftypes.NewValue(tftypes.Bool, deletionProtection),
+		})
+	}
+	request := func(state, plan tftypes.Value) resource.ModifyPlanRequest {
+		return resource.ModifyPlanRequest{State: tfsdk.State{Raw: state}, Plan: tfsdk.Plan{Raw: plan}}
+	}
+
+	testCases := map[string]struct {
+		req      resource.ModifyPlanRequest
+		expected bool
+	}{
+		"create":       {req: request(tftypes.NewValue(objectType, nil), object("new", false)), expected: true},
+		"remote":       {req: request(object("old", false), object("new", false)), expected: true},
+		"local":        {req: request(object("old", false), object("old", true))},
+		"delete":       {req: request(object("old", false), tftypes.NewValue(objectType, nil))},
+		"no change":    {req: request(object("old", false), object("old", false))},
+		"remote+local": {req: request(object("old", false), object("new", true)), expected: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if changed := remoteChangePlanned(testCase.req, "deletion_protection"); changed != testCase.expected {
+				t.Fatalf("expected %t, got %t", testCase.expected, changed)
+			}
+		})
+	}
+}
+
+func TestUpdateLocalAttributes(t *testing.T) {
+	ctx := context.Background()
+	r := &PasswordResource{}
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	password := func(deletionProtection bool) tftypes.Value {
+		values := map[string]tftypes.Value{}
+		for name, attributeType := range objectType.AttributeTypes {
+			values[name] = tftypes.NewValue(attributeType, nil)
+		}
+		values["id"] = tftypes.NewValue(tftypes.String, "abc")
+		values["name"] = tftypes.NewValue(tftypes.String, "test")
+		values["deletion_protection"] = tftypes.NewValue(tftypes.Bool, deletionProtection)
+		return tftypes.NewValue(objectType, values)
+	}
+
+	// Without a client, the update fails, if it sends a request to Passwork
+	req := resource.UpdateRequest{
+		Plan:  tfsdk.Plan{Schema: schemaResp.Schema, Raw: password(true)},
+		State: tfsdk.State{Schema: schemaResp.Schema, Raw: password(false)},
+	}
+	resp := resource.UpdateResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: req.State.Raw}}
+	r.Update(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var state PasswordResourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if !state.DeletionProtection.ValueBool() || state.Name.ValueString() != "test" {
+		t.Fatalf("unexpected state %+v", state)
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// savePartialState saves the planned values with the Id of an object, which was created in
+// Passwork, when a later step of the create fails. Unknown values are saved as null, unless
+// they are given in values, e.g. a generated master password. As the create fails, Terraform
+// marks the resource as tainted and replaces it on the next apply, so the object is neither
+// orphaned nor created a second time. deletion_protection is saved as false, so the tainted
+// object can be destroyed by the replacement.
+func savePartialState(ctx context.Context, plan tfsdk.Plan, resp *resource.CreateResponse, kind, id string, values map[string]attr.Value) {
+	raw, err := tftypes.Transform(plan.Raw, func(_ *tftypes.AttributePath, value tftypes.Value) (tftypes.Value, error) {
+		if !value.IsKnown() {
+			return tftypes.NewValue(value.Type(), nil), nil
+		}
+		return value, nil
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Saving Partial State",
+			"The "+kind+" "+id+" was created in Passwork, but could not be saved to the state: "+err.Error()+". "+
+				"Import it with terraform import or delete it in Passwork, before applying again.",
+		)
+		return
+	}
+
+	resp.State.Raw = raw
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("deletion_protection"), false)...)
+	for name, value := range values {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(name), value)...)
+	}
+	resp.Diagnostics.AddWarning(
+		"Passwork "+kind+" Partially Created",
+		"The "+kind+" "+id+" was created in Passwork, but a later step failed. "+
+			"Its Id was saved to the state and the resource is marked as tainted, so it is replaced on the next apply.",
+	)
+}
@@ -0,0 +1,56 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestSavePartialState(t *testing.T) {
+	ctx := context.Background()
+
+	var schemaResp resource.SchemaResponse
+	(&VaultResource{}).Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	// Computed values are unknown when planning the create
+	values := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(attributeType, tftypes.UnknownValue)
+	}
+	values["name"] = tftypes.NewValue(tftypes.String, "vault")
+	values["deletion_protection"] = tftypes.NewValue(tftypes.Bool, true)
+
+	resp := resource.CreateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+	savePartialState(ctx, tfsdk.Plan{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, values)}, &resp, "vault", "abc", map[string]attr.Value{
+		"master_password": types.StringValue("generated"),
+	})
+	if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 1 {
+		t.Fatalf("expected a single warning, got %v", resp.Diagnostics)
+	}
+
+	if !resp.State.Raw.IsFullyKnown() {
+		t.Fatal("expected unknown values to be saved as null")
+	}
+
+	var state VaultResourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	if state.Id.ValueString() != "abc" || state.Name.ValueString() != "vault" || !state.Access.IsNull() {
+		t.Fatalf("unexpected state %+v", state)
+	}
+	// The tainted vault must be deletable by its replacement
+	if state.DeletionProtection.ValueBool() || state.MasterPassword.ValueString() != "generated" {
+		t.Fatalf("unexpected state %+v", state)
+	}
+}
@@ -2,13 +2,13 @@ package provider
 
 import (
 	"context"
-	"encoding/base64"
 	"fmt"
 
 	"github.com/lupa95/passwork-client-go"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -25,7 +25,7 @@ func NewPasswordDataSource() datasource.DataSource {
 
 // passwordDataSource is the data source implementation.
 type passwordDataSource struct {
-	client *passwork.Client
+	client *passworkClient
 }
 
 // Metadata returns the data source type name.
@@ -44,7 +44,7 @@ func (d *passwordDataSource) Schema(_ context.Context, _ datasource.SchemaReques
 			},
 			"name": schema.StringAttribute{
 				Optional:            true,
-				MarkdownDescription: "The name of the password entry. If `id` is not supplied, password will be searched by name (best effort). Either `id` or `name` must be set.",
+				MarkdownDescription: "The name of the password entry. If `id` is not supplied, password will be searched by name (best effort). Passwords in vaults, which are not allowed by the provider configuration, are skipped. Either `id` or `name` must be set.",
 			},
 			"password": schema.StringAttribute{
 				Computed:    true,
@@ -67,10 +67,10 @@ func (d *passwordDataSource) Schema(_ context.Context, _ datasource.SchemaReques
 				Computed:    true,
 				Description: "The description of the password entry.",
 			},
-			"tags": schema.ListAttribute{
+			"tags": schema.SetAttribute{
 				Computed:    true,
 				ElementType: types.StringType,
-				Description: "The list of tags, which are assigned to the password entry.",
+				Description: "The set of tags, which are assigned to the password entry. Surrounding whitespace is trimmed and tags, which only differ in case, are returned once.",
 			},
 			"access": schema.StringAttribute{
 				Computed:    true,
@@ -87,6 +87,9 @@ func (d *passwordDataSource) Schema(_ context.Context, _ datasource.SchemaReques
 
 // Read refreshes the Terraform state with the latest data.
 func (d *passwordDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, span := startSpan(ctx, "PasswordDataSource.Read", "passwork_password")
+	defer func() { endSpan(ctx, span, resp.Diagnostics) }()
+
 	// Retrieve values from plan
 	var plan passwordDataSourceModel
 	diags := req.Config.Get(ctx, &plan)
@@ -95,6 +98,15 @@ func (d *passwordDataSource) Read(ctx context.Context, req datasource.ReadReques
 		return
 	}
 
+	setSpanAttributes(span, map[string]string{"id": plan.Id.ValueString(), "vault_id": plan.VaultId.ValueString()})
+
+	if !plan.VaultId.IsNull() && !plan.VaultId.IsUnknown() {
+		addVaultError(&resp.Diagnostics, path.Root("vault_id"), d.client.checkVault(ctx, plan.VaultId.ValueString()))
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	// Setup passwork data models
 	var getResponse passwork.PasswordResponse
 	var searchResponse passwork.PasswordSearchResponse
@@ -103,9 +115,9 @@ func (d *passwordDataSource) Read(ctx context.Context, req datasource.ReadReques
 
 	// If id is missing, search by name
 	if !plan.Id.IsNull() {
-		getResponse, err = d.client.GetPassword(plan.Id.ValueString())
+		getResponse, err = d.client.GetPassword(ctx, plan.Id.ValueString())
 		if err != nil {
-			resp.Diagnostics.AddError(ParsePasswordResponseError(err))
+			resp.Diagnostics.AddError(ParseAPIError(err, "read password "+plan.Id.ValueString()))
 			return
 		}
 	} else if plan.Id.IsNull() && !plan.Name.IsNull() {
@@ -113,14 +125,29 @@ func (d *passwordDataSource) Read(ctx context.Context, req datasource.ReadReques
 		if !plan.VaultId.IsUnknown() {
 			searchRequest.VaultId = plan.VaultId.ValueString()
 		}
-		searchResponse, err = d.client.SearchPassword(searchRequest)
+		searchResponse, err = d.client.SearchPassword(ctx, searchRequest)
+		if err != nil {
+			resp.Diagnostics.AddError(ParseAPIError(err, "search password "+plan.Name.ValueString()))
+			return
+		}
+		// Passwords in vaults, which are not allowed, are skipped before choosing the match
+		passwords, skipped, err := d.client.allowedPasswords(ctx, searchResponse.Data)
 		if err != nil {
-			resp.Diagnostics.AddError(ParsePasswordResponseError(err))
+			addVaultError(&resp.Diagnostics, path.Root("vault_id"), err)
+			return
+		}
+		if len(passwords) == 0 {
+			detail := "No password named " + plan.Name.ValueString() + " was found in Passwork. Check the name and vault_id, and that the current user has access to the password."
+			if skipped > 0 {
+				detail = fmt.Sprintf("No password named %s was found in the vaults allowed by the provider configuration. %d passwords in other vaults were skipped. "+
+					"Set vault_id or allow the vault in the provider configuration.", plan.Name.ValueString(), skipped)
+			}
+			resp.Diagnostics.AddError("Passwork Password Not Found", detail)
 			return
 		}
-		getResponse, err = d.client.GetPassword(searchResponse.Data[0].Id)
+		getResponse, err = d.client.GetPassword(ctx, passwords[0].Id)
 		if err != nil {
-			resp.Diagnostics.AddError(ParsePasswordResponseError(err))
+			resp.Diagnostics.AddError(ParseAPIError(err, "read password "+passwords[0].Id))
 			return
 		}
 	} else {
@@ -131,18 +158,26 @@ func (d *passwordDataSource) Read(ctx context.Context, req datasource.ReadReques
 		return
 	}
 
-	// Decode base64 password
-	decryptedPassword, err := base64.StdEncoding.DecodeString(getResponse.Data.CryptedPassword)
+	setSpanAttributes(span, map[string]string{"id": getResponse.Data.Id, "vault_id": getResponse.Data.VaultId})
+
+	// Passwords found by Id or name are checked with the vault returned by the API
+	addVaultError(&resp.Diagnostics, path.Root("vault_id"), d.client.checkVault(ctx, getResponse.Data.VaultId))
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Decrypt password
+	decryptedPassword, err := d.client.decryptPassword(ctx, getResponse.Data)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Pasword search error.",
-			"Could not decode password "+err.Error(),
+			"Could not decrypt password "+err.Error(),
 		)
 		return
 	}
 
 	// Update State
-	plan.Password = types.StringValue(string(decryptedPassword))
+	plan.Password = types.StringValue(decryptedPassword)
 	plan.Id = types.StringValue(getResponse.Data.Id)
 	plan.VaultId = types.StringValue(getResponse.Data.VaultId)
 	plan.Name = types.StringValue(getResponse.Data.Name)
@@ -151,7 +186,7 @@ func (d *passwordDataSource) Read(ctx context.Context, req datasource.ReadReques
 	plan.Description = types.StringValue(getResponse.Data.Description)
 	plan.Access = types.StringValue(getResponse.Data.Access)
 	plan.AccessCode = types.Int32Value(int32(getResponse.Data.AccessCode))
-	plan.Tags, _ = types.ListValueFrom(ctx, types.StringType, getResponse.Data.Tags)
+	plan.Tags, _ = types.SetValueFrom(ctx, types.StringType, normalizeTags(getResponse.Data.Tags))
 
 	// Set state
 	diags = resp.State.Set(ctx, &plan)
@@ -167,11 +202,11 @@ func (d *passwordDataSource) Configure(_ context.Context, req datasource.Configu
 		return
 	}
 
-	client, ok := req.ProviderData.(*passwork.Client)
+	client, ok := req.ProviderData.(*passworkClient)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *passwork.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *passworkClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
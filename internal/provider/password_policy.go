@@ -0,0 +1,192 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Levels of the password policy, which decide how violations are reported.
+const (
+	passwordPolicyLevelWarning = "warning"
+	passwordPolicyLevelError   = "error"
+)
+
+// passwordPolicyModel describes the password_policy block of the provider configuration.
+type passwordPolicyModel struct {
+	MinLength             types.Int32  `tfsdk:"min_length"`
+	RequireLowercase      types.Bool   `tfsdk:"require_lowercase"`
+	RequireUppercase      types.Bool   `tfsdk:"require_uppercase"`
+	RequireDigits         types.Bool   `tfsdk:"require_digits"`
+	RequireSymbols        types.Bool   `tfsdk:"require_symbols"`
+	MaxRepeatedCharacters types.Int32  `tfsdk:"max_repeated_characters"`
+	DenyListFile          types.String `tfsdk:"deny_list_file"`
+	Level                 types.String `tfsdk:"level"`
+}
+
+// passwordPolicy is checked for the passwords of password entries, when they are planned.
+type passwordPolicy struct {
+	minLength        int
+	requireLowercase bool
+	requireUppercase bool
+	requireDigits    bool
+	requireSymbols   bool
+	// maxRepeatedCharacters is the longest allowed run of the same character, 0 for no limit.
+	maxRepeatedCharacters int
+	// denyList contains the denied passwords in lower case.
+	denyList map[string]bool
+	level    string
+}
+
+func newPasswordPolicy(model passwordPolicyModel) (*passwordPolicy, error) {
+	policy := &passwordPolicy{
+		minLength:             int(model.MinLength.ValueInt32()),
+		requireLowercase:      model.RequireLowercase.ValueBool(),
+		requireUppercase:      model.RequireUppercase.ValueBool(),
+		requireDigits:         model.RequireDigits.ValueBool(),
+		requireSymbols:        model.RequireSymbols.ValueBool(),
+		maxRepeatedCharacters: int(model.MaxRepeatedCharacters.ValueInt32()),
+		level:                 model.Level.ValueString(),
+	}
+	if policy.level == "" {
+		policy.level = passwordPolicyLevelError
+	}
+
+	if file := model.DenyListFile.ValueString(); file != "" {
+		denyList, err := readDenyList(file)
+		if err != nil {
+			return nil, err
+		}
+		policy.denyList = denyList
+	}
+
+	return policy, nil
+}
+
+// readDenyList reads a file with one denied password per line. Empty lines and lines starting with # are skipped.
+func readDenyList(file string) (map[string]bool, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	denyList := map[string]bool{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		denyList[strings.ToLower(line)] = true
+	}
+
+	return denyList, scanner.Err()
+}
+
+// violations lists the rules of the policy, which the password does not comply with.
+// The password itself is never part of the result.
+func (p *passwordPolicy) violations(password string) []string {
+	var violations []string
+
+	if length := len([]rune(password)); length < p.minLength {
+		violations = append(violations, fmt.Sprintf("must be at least %d characters long, but is %d characters long", p.minLength, length))
+	}
+
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+	for _, class := range []struct {
+		required bool
+		found    bool
+		name     string
+	}{
+		{p.requireLowercase, lower, "a lowercase letter"},
+		{p.requireUppercase, upper, "an uppercase letter"},
+		{p.requireDigits, digit, "a digit"},
+		{p.requireSymbols, symbol, "a symbol"},
+	} {
+		if class.required && !class.found {
+			violations = append(violations, "must contain "+class.name)
+		}
+	}
+
+	if p.maxRepeatedCharacters > 0 {
+		if run := longestRun(password); run > p.maxRepeatedCharacters {
+			violations = append(violations, fmt.Sprintf("must not repeat the same character more than %d times in a row, but repeats a character %d times", p.maxRepeatedCharacters, run))
+		}
+	}
+
+	if p.denyList[strings.ToLower(password)] {
+		violations = append(violations, "must not be a value from the deny list")
+	}
+
+	return violations
+}
+
+// longestRun returns the length of the longest run of the same character.
+func longestRun(value string) int {
+	longest, run := 0, 0
+	var previous rune
+	for i, r := range []rune(value) {
+		if i > 0 && r == previous {
+			run++
+		} else {
+			run = 1
+		}
+		previous = r
+		longest = max(longest, run)
+	}
+
+	return longest
+}
+
+// checkPlannedPassword reports violations of the password policy, if a password entry is
+// created or its password is changed. Unknown passwords are checked, once they are known.
+func checkPlannedPassword(ctx context.Context, client *passworkClient, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if client == nil || client.passwordPolicy == nil || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var planPassword, statePassword types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("password"), &planPassword)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("password"), &statePassword)...)
+	}
+	if resp.Diagnostics.HasError() || planPassword.IsNull() || planPassword.IsUnknown() || planPassword.Equal(statePassword) {
+		return
+	}
+
+	violations := client.passwordPolicy.violations(planPassword.ValueString())
+	if len(violations) == 0 {
+		return
+	}
+
+	summary := "Password Violates Password Policy"
+	detail := "The password does not comply with the password_policy of the provider configuration. The password:\n- " + strings.Join(violations, "\n- ")
+	if client.passwordPolicy.level == passwordPolicyLevelWarning {
+		resp.Diagnostics.AddAttributeWarning(path.Root("password"), summary, detail)
+	} else {
+		resp.Diagnostics.AddAttributeError(path.Root("password"), summary, detail)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestPasswordPolicyViolations(t *testing.T) {
+	denyListFile := filepath.Join(t.TempDir(), "deny-list.txt")
+	if err := os.WriteFile(denyListFile, []byte("# Default passwords\n\nChangeMe123!\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	policy, err := newPasswordPolicy(passwordPolicyModel{
+		MinLength:             types.Int32Value(10),
+		RequireLowercase:      types.BoolValue(true),
+		RequireUppercase:      types.BoolValue(true),
+		RequireDigits:         types.BoolValue(true),
+		RequireSymbols:        types.BoolValue(true),
+		MaxRepeatedCharacters: types.Int32Value(2),
+		DenyListFile:          types.StringValue(denyListFile),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if policy.level != passwordPolicyLevelError {
+		t.Fatalf("expected level to default to error, got %q", policy.level)
+	}
+
+	testCases := map[string]struct {
+		password   string
+		violations []string
+	}{
+		"compliant":  {password: "Correct-Horse-42"},
+		"too short":  {password: "Ab1!", violations: []string{"must be at least 10 characters long, but is 4 characters long"}},
+		"no classes": {password: "abcdefghij", violations: []string{"must contain an uppercase letter", "must contain a digit", "must contain a symbol"}},
+		"repeated":   {password: "Abc-1234-xxx", violations: []string{"must not repeat the same character more than 2 times in a row, but repeats a character 3 times"}},
+		"denied":     {password: "changeme123!", violations: []string{"must contain an uppercase letter", "must not be a value from the deny list"}},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			violations := policy.violations(testCase.password)
+			if strings.Join(violations, "\n") != strings.Join(testCase.violations, "\n") {
+				t.Fatalf("expected violations %q, got %q", testCase.violations, violations)
+			}
+		})
+	}
+
+	if _, err := newPasswordPolicy(passwordPolicyModel{DenyListFile: types.StringValue(filepath.Join(t.TempDir(), "missing"))}); err == nil {
+		t.Fatal("expected error for missing deny list file")
+	}
+}
@@ -5,15 +5,20 @@ package provider
 
 import (
 	"context"
-	"encoding/base64"
+	"errors"
 	"fmt"
+	"maps"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/lupa95/passwork-client-go"
 )
@@ -21,14 +26,20 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &PasswordResource{}
 var _ resource.ResourceWithImportState = &PasswordResource{}
+var _ resource.ResourceWithModifyPlan = &PasswordResource{}
+var _ resource.ResourceWithUpgradeState = &PasswordResource{}
+var _ resource.ResourceWithConfigValidators = &PasswordResource{}
 
 func NewPasswordResource() resource.Resource {
 	return &PasswordResource{}
 }
 
+// passwordLocalAttributes are only stored in the Terraform state and never sent to Passwork.
+var passwordLocalAttributes = []string{"deletion_protection", "overwrite_remote_changes", "timeouts"}
+
 // ExampleResource defines the resource implementation.
 type PasswordResource struct {
-	client *passwork.Client
+	client *passworkClient
 }
 
 func (r *PasswordResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -37,8 +48,16 @@ func (r *PasswordResource) Metadata(ctx context.Context, req resource.MetadataRe
 
 func (r *PasswordResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		// Version 1 stores the tags as set instead of list
+		Version:     1,
 		Description: "Use this resource to create a password entry. Passwords need to be stored inside a vault.",
 		Attributes: map[string]schema.Attribute{
+			"deletion_protection": schema.BoolAttribute{
+				Description: "Prevents the password from being deleted, while set to `true`. Set it to `false` and apply the change, before the password can be destroyed or removed from the configuration. Defaults to `false`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
 			"name": schema.StringAttribute{
 				Description: "The name of the password entry.",
 				Required:    true,
@@ -46,6 +65,9 @@ func (r *PasswordResource) Schema(ctx context.Context, req resource.SchemaReques
 			"vault_id": schema.StringAttribute{
 				Description: "The Id of the vault, which the password entry should be stored in.",
 				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
 			"id": schema.StringAttribute{
 				Description: "The Id of the password entry.",
@@ -88,16 +110,35 @@ func (r *PasswordResource) Schema(ctx context.Context, req resource.SchemaReques
 				Description: "The color code of the password entry.",
 				Optional:    true,
 			},
-			"tags": schema.ListAttribute{
-				Description: "The list of tags, which are assigned to the password entry.",
+			"tags": schema.SetAttribute{
+				Description: "The set of tags, which are assigned to the password entry. Surrounding whitespace is trimmed and tags, which only differ in case, are treated as the same tag.",
+				CustomType:  newTagsType(),
 				ElementType: types.StringType,
 				Optional:    true,
 			},
 			"folder_id": schema.StringAttribute{
-				Description: "The Id of the folder, which the password entry should be stored in.",
+				Description: "The Id of the folder, which the password entry should be stored in. The folder must belong to the vault given by `vault_id`, which is checked when planning.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"overwrite_remote_changes": schema.BoolAttribute{
+				Description: "Enable to update the password entry, even if it was changed in Passwork since it was last read by Terraform. Otherwise the update fails and lists the changed fields. Remote changes of the password itself are only detected, if Passwork reports the time of the last password change, which the Passwork 7 API does not. Defaults to `false`.",
 				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx),
+		},
+	}
+}
+
+func (r *PasswordResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		folderVaultValidator{folderAttribute: "folder_id"},
 	}
 }
 
@@ -107,11 +148,11 @@ func (r *PasswordResource) Configure(ctx context.Context, req resource.Configure
 		return
 	}
 
-	client, ok := req.ProviderData.(*passwork.Client)
+	client, ok := req.ProviderData.(*passworkClient)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *passwork.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *passworkClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -121,6 +162,9 @@ func (r *PasswordResource) Configure(ctx context.Context, req resource.Configure
 }
 
 func (r *PasswordResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startSpan(ctx, "PasswordResource.Create", "passwork_password")
+	defer func() { endSpan(ctx, span, resp.Diagnostics) }()
+
 	var (
 		plan     PasswordResourceModel
 		newState PasswordResourceModel
@@ -136,29 +180,68 @@ func (r *PasswordResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
-	// Create request from model
-	request = PasswordModelToRequest(plan)
+	setSpanAttributes(span, map[string]string{"vault_id": plan.VaultId.ValueString()})
 
-	// Send request
-	response, err = r.client.AddPassword(request)
+	// Bound the whole operation including retries and follow-up requests
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	// Serialize writes within the vault, if enabled
+	unlock, err := r.client.lockVault(ctx, plan.VaultId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(ParseAPIError(err, "lock vault "+plan.VaultId.ValueString()))
+		return
+	}
+	defer unlock()
+
+	// Create request from model
+	request, err = PasswordModelToRequest(ctx, plan, r.client, "")
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Error Creating Password",
-			"Could not update password, unexpected error: "+response.Code,
+			"Error encrypting Password",
+			"Could not encrypt password value, unexpected error: "+err.Error(),
 		)
 		return
 	}
 
+	// Send request
+	response, err = r.client.AddPassword(ctx, request)
+	setSpanAttributes(span, map[string]string{"id": response.Data.Id})
+	if err != nil {
+		resp.Diagnostics.AddError(ParseAPIError(err, "create password "+plan.Name.ValueString()))
+		// A retry can detect that the entry was created, although reading it failed
+		if response.Data.Id != "" {
+			savePartialState(ctx, req.Plan, resp, "password", response.Data.Id, nil)
+		}
+		return
+	}
+
 	// Convert response to state
-	newState, err = PasswordResponseToModel(response)
+	newState, err = PasswordResponseToModel(ctx, response, r.client)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error converting Password response into state",
 			"Could not update state with API response, unexpected error: "+err.Error(),
 		)
+		savePartialState(ctx, req.Plan, resp, "password", response.Data.Id, nil)
 		return
 	}
 
+	// deletion_protection is not stored in Passwork
+	newState.DeletionProtection = plan.DeletionProtection
+
+	newState.OverwriteRemoteChanges = plan.OverwriteRemoteChanges
+
+	// Remember the version of the entry to detect remote changes before the next update
+	resp.Diagnostics.Append(setRemoteVersion(ctx, resp.Private, response.Data)...)
+
+	newState.Timeouts = plan.Timeouts
+
 	// Set refreshed state
 	diags = resp.State.Set(ctx, newState)
 	resp.Diagnostics.Append(diags...)
@@ -168,6 +251,9 @@ func (r *PasswordResource) Create(ctx context.Context, req resource.CreateReques
 }
 
 func (r *PasswordResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startSpan(ctx, "PasswordResource.Read", "passwork_password")
+	defer func() { endSpan(ctx, span, resp.Diagnostics) }()
+
 	var (
 		state    PasswordResourceModel
 		newState PasswordResourceModel
@@ -182,23 +268,38 @@ func (r *PasswordResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
+	setSpanAttributes(span, map[string]string{"id": state.Id.ValueString(), "vault_id": state.VaultId.ValueString()})
+
+	// Bound the whole operation including retries and follow-up requests
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
 	// Get refreshed password value from Passwork
-	response, err = r.client.GetPassword(state.Id.ValueString())
+	response, err = r.client.GetPassword(ctx, state.Id.ValueString())
 
 	// Check for errors
 	if err != nil {
-		resp.Diagnostics.AddError(ParsePasswordResponseError(err))
+		// Remove resource from state, if it was deleted outside of Terraform
+		if removeNotFound(ctx, resp, "password", state.Id.ValueString(), err) {
+			return
+		}
+		resp.Diagnostics.AddError(ParseAPIError(err, "read password "+state.Id.ValueString()))
 		return
 	}
 
-	// Remove resource from state if error is returned from Passwork
-	if response.Status != "success" {
-		resp.State.RemoveResource(ctx)
+	// The vault of the password is only known from the API, e.g. after an import
+	addVaultError(&resp.Diagnostics, path.Root("vault_id"), r.client.checkVault(ctx, response.Data.VaultId))
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	// Convert response to state
-	newState, err = PasswordResponseToModel(response)
+	newState, err = PasswordResponseToModel(ctx, response, r.client)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error converting Password response into state",
@@ -207,6 +308,16 @@ func (r *PasswordResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
+	// deletion_protection is not stored in Passwork and defaults to false for imported passwords
+	newState.DeletionProtection = localBoolState(state.DeletionProtection)
+
+	newState.OverwriteRemoteChanges = localBoolState(state.OverwriteRemoteChanges)
+
+	// Remember the version of the entry to detect remote changes before the next update
+	resp.Diagnostics.Append(setRemoteVersion(ctx, resp.Private, response.Data)...)
+
+	newState.Timeouts = state.Timeouts
+
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &newState)
 	resp.Diagnostics.Append(diags...)
@@ -216,6 +327,9 @@ func (r *PasswordResource) Read(ctx context.Context, req resource.ReadRequest, r
 }
 
 func (r *PasswordResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startSpan(ctx, "PasswordResource.Update", "passwork_password")
+	defer func() { endSpan(ctx, span, resp.Diagnostics) }()
+
 	var (
 		plan     PasswordResourceModel
 		newState PasswordResourceModel
@@ -231,18 +345,100 @@ func (r *PasswordResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
+	setSpanAttributes(span, map[string]string{"id": plan.Id.ValueString(), "vault_id": plan.VaultId.ValueString()})
+
+	// Changes of attributes, which are only stored in the state, are not sent to Passwork
+	if !remoteAttributesChanged(req.Plan.Raw, req.State.Raw, passwordLocalAttributes...) {
+		var state PasswordResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		state.DeletionProtection = plan.DeletionProtection
+		state.OverwriteRemoteChanges = plan.OverwriteRemoteChanges
+		state.Timeouts = plan.Timeouts
+		resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+		return
+	}
+
+	// Bound the whole operation including retries and follow-up requests
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	// Serialize writes within the vault, if enabled
+	unlock, err := r.client.lockVault(ctx, plan.VaultId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(ParseAPIError(err, "lock vault "+plan.VaultId.ValueString()))
+		return
+	}
+	defer unlock()
+
+	// Fail instead of silently overwriting changes made in Passwork since the last refresh
+	if !plan.OverwriteRemoteChanges.ValueBool() {
+		version, diags := getRemoteVersion(ctx, req.Private)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if version != nil {
+			current, err := r.client.GetPassword(ctx, plan.Id.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddError(ParseAPIError(err, "read password "+plan.Id.ValueString()))
+				return
+			}
+
+			if changes := remoteChanges(*version, current.Data); len(changes) > 0 {
+				resp.Diagnostics.AddError(
+					"Password Changed Remotely",
+					"The password entry "+plan.Id.ValueString()+" was changed in Passwork since it was last read by Terraform:\n\n"+strings.Join(changes, "\n")+"\n\n"+
+						"Run terraform plan again to review the changes against the current values, or set overwrite_remote_changes = true to overwrite them.",
+				)
+				return
+			}
+		}
+	}
+
+	// Entries with their own key must stay encrypted with it
+	cryptedKey, err := r.client.entryKey(ctx, plan.Id.ValueString(), plan.VaultId.ValueString())
+	var moveErr *entryKeyMoveError
+	if errors.As(err, &moveErr) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("vault_id"),
+			"Unsupported Password Move",
+			"Could not move the password entry to vault "+plan.VaultId.ValueString()+": "+err.Error()+". Create a new entry in the vault instead.",
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(ParseAPIError(err, "read the key of password "+plan.Id.ValueString()))
+		return
+	}
+
 	// Create request from state
-	request = PasswordModelToRequest(plan)
+	request, err = PasswordModelToRequest(ctx, plan, r.client, cryptedKey)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error encrypting Password",
+			"Could not encrypt password value, unexpected error: "+err.Error(),
+		)
+		return
+	}
 
 	// Send request
-	response, err = r.client.EditPassword(plan.Id.ValueString(), request)
+	response, err = r.client.EditPassword(ctx, plan.Id.ValueString(), request)
 	if err != nil {
-		resp.Diagnostics.AddError(ParsePasswordResponseError(err))
+		resp.Diagnostics.AddError(ParseAPIError(err, "update password "+plan.Id.ValueString()))
 		return
 	}
 
 	// Convert response to state
-	newState, err = PasswordResponseToModel(response)
+	newState, err = PasswordResponseToModel(ctx, response, r.client)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error converting Password response into state",
@@ -251,6 +447,16 @@ func (r *PasswordResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
+	// deletion_protection is not stored in Passwork
+	newState.DeletionProtection = plan.DeletionProtection
+
+	newState.OverwriteRemoteChanges = plan.OverwriteRemoteChanges
+
+	// Remember the version of the entry to detect remote changes before the next update
+	resp.Diagnostics.Append(setRemoteVersion(ctx, resp.Private, response.Data)...)
+
+	newState.Timeouts = plan.Timeouts
+
 	// Set refreshed state
 	diags = resp.State.Set(ctx, newState)
 	resp.Diagnostics.Append(diags...)
@@ -260,6 +466,9 @@ func (r *PasswordResource) Update(ctx context.Context, req resource.UpdateReques
 }
 
 func (r *PasswordResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startSpan(ctx, "PasswordResource.Delete", "passwork_password")
+	defer func() { endSpan(ctx, span, resp.Diagnostics) }()
+
 	var (
 		plan PasswordResourceModel
 		err  error
@@ -272,21 +481,112 @@ func (r *PasswordResource) Delete(ctx context.Context, req resource.DeleteReques
 		return
 	}
 
-	// Send delete request
-	_, err = r.client.DeletePassword(plan.Id.ValueString())
+	setSpanAttributes(span, map[string]string{"id": plan.Id.ValueString(), "vault_id": plan.VaultId.ValueString()})
+
+	// Bound the whole operation including retries and follow-up requests
+	deleteTimeout, diags := plan.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	if plan.DeletionProtection.ValueBool() {
+		addDeletionProtectionError(&resp.Diagnostics, "Password", plan.Id.ValueString())
+		return
+	}
+
+	// Serialize writes within the vault, if enabled
+	unlock, err := r.client.lockVault(ctx, plan.VaultId.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError(ParsePasswordResponseError(err))
+		resp.Diagnostics.AddError(ParseAPIError(err, "lock vault "+plan.VaultId.ValueString()))
+		return
+	}
+	defer unlock()
+
+	// Send delete request
+	_, err = r.client.DeletePassword(ctx, plan.Id.ValueString())
+	// A retried delete finds the password already deleted by the first attempt
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError(ParseAPIError(err, "delete password "+plan.Id.ValueString()))
 		return
 	}
 }
 
+func (r *PasswordResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkReadOnly(r.client, req, resp, "passwork_password")
+	checkPlannedVault(ctx, r.client, req, resp, "vault_id")
+	checkPlannedFolderVault(ctx, r.client, req, resp, "folder_id")
+	checkPlannedPermissions(ctx, r.client, req, resp, "folder_id", passwordLocalAttributes...)
+	checkPlannedPassword(ctx, r.client, req, resp)
+}
+
 func (r *PasswordResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
-func PasswordModelToRequest(model PasswordResourceModel) passwork.PasswordRequest {
-	// Encode base64 password
-	cryptedPassword := base64.StdEncoding.EncodeToString([]byte(model.Password.ValueString()))
+func (r *PasswordResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	// Version 0 only differs in the tags, which were stored as list
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	priorSchema := schemaResp.Schema
+	priorSchema.Version = 0
+	priorSchema.Attributes = maps.Clone(priorSchema.Attributes)
+	priorSchema.Attributes["tags"] = schema.ListAttribute{
+		ElementType: types.StringType,
+		Optional:    true,
+	}
+
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema:   &priorSchema,
+			StateUpgrader: upgradePasswordStateV0,
+		},
+	}
+}
+
+func upgradePasswordStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var priorState passwordResourceModelV0
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &priorState)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var tags []string
+	for _, tag := range priorState.Tags {
+		tags = append(tags, tag.ValueString())
+	}
+
+	upgradedState := PasswordResourceModel{
+		VaultId:                priorState.VaultId,
+		FolderId:               priorState.FolderId,
+		Id:                     priorState.Id,
+		Name:                   priorState.Name,
+		Login:                  priorState.Login,
+		Password:               priorState.Password,
+		Description:            priorState.Description,
+		Url:                    priorState.Url,
+		Color:                  priorState.Color,
+		Tags:                   newTagsValue(tags),
+		Access:                 priorState.Access,
+		AccessCode:             priorState.AccessCode,
+		DeletionProtection:     priorState.DeletionProtection,
+		OverwriteRemoteChanges: priorState.OverwriteRemoteChanges,
+		Timeouts:               priorState.Timeouts,
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, upgradedState)...)
+}
+
+func PasswordModelToRequest(ctx context.Context, model PasswordResourceModel, client *passworkClient, cryptedKey string) (passwork.PasswordRequest, error) {
+	// Encrypt password, base64 encoded if client-side encryption is disabled
+	cryptedPassword, err := client.encryptPassword(ctx, model.VaultId.ValueString(), cryptedKey, model.Password.ValueString())
+	if err != nil {
+		return passwork.PasswordRequest{}, err
+	}
 
 	// Generate API request body from model
 	var request = passwork.PasswordRequest{
@@ -300,24 +600,22 @@ func PasswordModelToRequest(model PasswordResourceModel) passwork.PasswordReques
 		FolderId:        model.FolderId.ValueString(),
 	}
 
-	for _, tag := range model.Tags {
-		request.Tags = append(request.Tags, tag.ValueString())
-	}
+	request.Tags = normalizeTags(model.Tags.Strings())
 
-	return request
+	return request, nil
 }
 
-func PasswordResponseToModel(response passwork.PasswordResponse) (PasswordResourceModel, error) {
+func PasswordResponseToModel(ctx context.Context, response passwork.PasswordResponse, client *passworkClient) (PasswordResourceModel, error) {
 	var model PasswordResourceModel
 
 	if response.Data.CryptedPassword == "" {
 		model.Password = types.StringNull()
 	} else {
-		decryptedPassword, err := base64.StdEncoding.DecodeString(response.Data.CryptedPassword)
+		decryptedPassword, err := client.decryptPassword(ctx, response.Data)
 		if err != nil {
 			return model, err
 		}
-		model.Password = types.StringValue(string(decryptedPassword))
+		model.Password = types.StringValue(decryptedPassword)
 	}
 
 	model.VaultId = types.StringValue(response.Data.VaultId)
@@ -350,22 +648,10 @@ func PasswordResponseToModel(response passwork.PasswordResponse) (PasswordResour
 		model.Color = types.Int32Value(int32(response.Data.Color))
 	}
 
-	for _, tag := range response.Data.Tags {
-		model.Tags = append(model.Tags, types.StringValue(tag))
-	}
+	model.Tags = newTagsValue(response.Data.Tags)
 
 	model.Access = types.StringValue(response.Data.Access)
 	model.AccessCode = types.Int32Value(int32(response.Data.AccessCode))
 
 	return model, nil
 }
-
-func ParsePasswordResponseError(err error) (summary, detail string) {
-	if err.Error() == "accessDenied" {
-		return "Password permission error.", "Could not create, update or read password. Make sure you have access to the password and vault."
-	} else if err.Error() == "passwordNull" {
-		return "Password not found error.", "Could not create, update or read password. Make sure the password Id is correct."
-	}
-
-	return "Unexpected error", "Could not create, update or read password. Error: " + err.Error()
-}
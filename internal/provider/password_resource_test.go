@@ -30,6 +30,7 @@ func TestPasswordResource(t *testing.T) {
 					resource.TestCheckResourceAttr("passwork_password.test", "color", "1"),
 					resource.TestCheckResourceAttr("passwork_password.test", "tags.#", "3"),
 					resource.TestCheckResourceAttrSet("passwork_password.test", "id"),
+					resource.TestCheckResourceAttr("passwork_password.test", "deletion_protection", "false"),
 					resource.TestCheckResourceAttrSet("passwork_password.test", "access"),
 					resource.TestCheckResourceAttrSet("passwork_password.test", "access_code"),
 				),
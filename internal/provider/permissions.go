@@ -0,0 +1,83 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// readOnlyVaultAccess are the access levels of vaults, which do not allow creating, changing
+// or deleting folders and passwords. Other levels are assumed to allow writing.
+var readOnlyVaultAccess = map[string]bool{
+	"read":     true,
+	"readonly": true,
+	"noaccess": true,
+}
+
+// permissionError is returned, if the current user may not write to a vault or folder.
+type permissionError struct {
+	kind   string
+	id     string
+	name   string
+	access string
+}
+
+func (e *permissionError) Error() string {
+	object := fmt.Sprintf("%s %q (%s)", e.kind, e.name, e.id)
+	if e.name == "" {
+		object = e.kind + " " + e.id
+	}
+
+	return fmt.Sprintf("the current user only has %s access to %s and cannot create, change or delete entries in it. Ask a vault administrator for write access or use another %s.", e.access, object, e.kind)
+}
+
+func vaultAccessAllowsWrite(access string) bool {
+	return !readOnlyVaultAccess[strings.ToLower(access)]
+}
+
+// checkVaultWriteAccess returns a permissionError, if the current user may not write to the vault.
+func (c *passworkClient) checkVaultWriteAccess(ctx context.Context, vaultId string) error {
+	vault, err := c.cachedVault(ctx, vaultId)
+	if errorKindOf(err) == errorKindForbidden {
+		return &permissionError{kind: "vault", id: vaultId, access: "no"}
+	}
+	if err != nil {
+		return err
+	}
+
+	if !vaultAccessAllowsWrite(vault.Access) {
+		return &permissionError{kind: "vault", id: vaultId, name: vault.Name, access: vault.Access}
+	}
+
+	return nil
+}
+
+// checkFolderWriteAccess returns a permissionError, if the current user may not write to the folder.
+// Passwork denies reading the folder itself, if the user has no access to it. Otherwise the access
+// level of its vault is checked, which also applies to its folders.
+func (c *passworkClient) checkFolderWriteAccess(ctx context.Context, folderId string) error {
+	folder, err := c.cachedFolder(ctx, folderId)
+	if errorKindOf(err) == errorKindForbidden {
+		return &permissionError{kind: "folder", id: folderId, access: "no"}
+	}
+	if err != nil {
+		return err
+	}
+
+	vault, err := c.cachedVault(ctx, folder.VaultId)
+	if errorKindOf(err) == errorKindForbidden {
+		return &permissionError{kind: "folder", id: folderId, name: folder.Name, access: "no"}
+	}
+	if err != nil {
+		return err
+	}
+
+	if !vaultAccessAllowsWrite(vault.Access) {
+		return &permissionError{kind: "folder", id: folderId, name: folder.Name, access: vault.Access}
+	}
+
+	return nil
+}
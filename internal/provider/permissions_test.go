@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/lupa95/passwork-client-go"
+)
+
+func TestCheckWriteAccess(t *testing.T) {
+	responses := map[string]string{
+		"/api/v4/vaults/writable":   `{"id":"writable","name":"Team","access":"write"}`,
+		"/api/v4/vaults/readable":   `{"id":"readable","name":"Shared","access":"read"}`,
+		"/api/v4/folders/admin":     `{"id":"admin","name":"Admin","vaultId":"writable","access":0}`,
+		"/api/v4/folders/read-only": `{"id":"read-only","name":"Read only","vaultId":"readable","access":0}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v4/folders/denied" {
+			fmt.Fprint(w, `{"status":"error","code":"accessDenied"}`)
+			return
+		}
+		data, ok := responses[r.URL.Path]
+		if !ok {
+			fmt.Fprint(w, `{"status":"error","code":"notFound"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"status":"success","data":%s}`, data)
+	}))
+	defer server.Close()
+
+	client := newPassworkClient(newV4Backend(passwork.NewClient(server.URL+"/api/v4", "test-key", 5*time.Second)), false, "")
+	ctx := context.Background()
+
+	var permission *permissionError
+	if err := client.checkVaultWriteAccess(ctx, "writable"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := client.checkVaultWriteAccess(ctx, "readable"); !errors.As(err, &permission) {
+		t.Fatalf("expected permissionError, got %v", err)
+	}
+	if err := client.checkFolderWriteAccess(ctx, "admin"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := client.checkFolderWriteAccess(ctx, "read-only"); !errors.As(err, &permission) {
+		t.Fatalf("expected permissionError, got %v", err)
+	}
+	if err := client.checkFolderWriteAccess(ctx, "denied"); !errors.As(err, &permission) {
+		t.Fatalf("expected permissionError for a folder without access, got %v", err)
+	}
+	if err := client.checkFolderWriteAccess(ctx, "missing"); err == nil || errors.As(err, &permission) {
+		t.Fatalf("expected API error, got %v", err)
+	}
+}
+
+func TestCheckPlannedPermissionsMissingFolder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v4/vaults/vault":
+			fmt.Fprint(w, `{"status":"success","data":{"id":"vault","name":"Team","access":"write"}}`)
+		default:
+			fmt.Fprint(w, `{"status":"error","code":"folderNotFound"}`)
+		}
+	}))
+	defer server.Close()
+
+	client := newPassworkClient(newV4Backend(passwork.NewClient(server.URL+"/api/v4", "test-key", 5*time.Second)), false, "")
+	ctx := context.Background()
+
+	folderSchema := schema.Schema{Attributes: map[string]schema.Attribute{
+		"name":      schema.StringAttribute{Required: true},
+		"vault_id":  schema.StringAttribute{Required: true},
+		"parent_id": schema.StringAttribute{Optional: true},
+	}}
+	objectType := folderSchema.Type().TerraformType(ctx)
+	folder := func(name, parentId string) tftypes.Value {
+		return tftypes.NewValue(objectType, map[string]tftypes.Value{
+			"name":      tftypes.NewValue(tftypes.String, name),
+			"vault_id":  tftypes.NewValue(tftypes.String, "vault"),
+			"parent_id": tftypes.NewValue(tftypes.String, parentId),
+		})
+	}
+
+	// The parent folder was deleted outside of Terraform
+	for name, plan := range map[string]tftypes.Value{"update": folder("new", "missing"), "delete": tftypes.NewValue(objectType, nil)} {
+		t.Run(name, func(t *testing.T) {
+			req := resource.ModifyPlanRequest{
+				State: tfsdk.State{Schema: folderSchema, Raw: folder("old", "missing")},
+				Plan:  tfsdk.Plan{Schema: folderSchema, Raw: plan},
+			}
+			var resp resource.ModifyPlanResponse
+			checkPlannedPermissions(ctx, client, req, &resp, "parent_id")
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+		})
+	}
+}
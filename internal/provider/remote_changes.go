@@ -0,0 +1,111 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/lupa95/passwork-client-go"
+)
+
+// remoteVersionKey is the private state key of the password entry version seen by the last read or write.
+const remoteVersionKey = "remote_version"
+
+// remoteVersion is a change marker of a password entry. It contains the values of the fields,
+// which are not secret and also stored in the state. The password is represented by the time
+// of its last change reported by Passwork, so neither it nor a hash of it is stored.
+type remoteVersion struct {
+	Values          map[string]string `json:"values"`
+	PasswordUpdated string            `json:"password_updated,omitempty"`
+}
+
+// passwordFields returns the fields of a password entry managed by the provider, except the password.
+func passwordFields(data passwork.PasswordResponseData) map[string]string {
+	return map[string]string{
+		"name":        data.Name,
+		"vault_id":    data.VaultId,
+		"folder_id":   data.FolderId,
+		"login":       data.Login,
+		"url":         data.Url,
+		"description": data.Description,
+		"color":       strconv.Itoa(data.Color),
+		"tags":        strings.Join(normalizeTags(data.Tags), ", "),
+	}
+}
+
+// passwordUpdated returns the time of the last password change. It is empty, if the API does not
+// report it, e.g. the Passwork 7 API, so remote changes of the password are not detected then.
+func passwordUpdated(data passwork.PasswordResponseData) string {
+	if data.LastPasswordUpdate == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(data.LastPasswordUpdate)
+}
+
+func newRemoteVersion(data passwork.PasswordResponseData) remoteVersion {
+	return remoteVersion{Values: passwordFields(data), PasswordUpdated: passwordUpdated(data)}
+}
+
+// remoteChanges lists the fields of the password entry, which changed since the given version.
+func remoteChanges(version remoteVersion, data passwork.PasswordResponseData) []string {
+	var changes []string
+	for name, value := range passwordFields(data) {
+		previous, ok := version.Values[name]
+		if !ok || previous == value {
+			continue
+		}
+
+		changes = append(changes, fmt.Sprintf("  ~ %s changed remotely from %q to %q", name, previous, value))
+	}
+
+	if updated := passwordUpdated(data); version.PasswordUpdated != "" && updated != version.PasswordUpdated {
+		changes = append(changes, "  ~ password changed remotely (value hidden)")
+	}
+	sort.Strings(changes)
+
+	return changes
+}
+
+// setRemoteVersion stores the change marker of the password entry in the private state.
+func setRemoteVersion(ctx context.Context, private interface {
+	SetKey(context.Context, string, []byte) diag.Diagnostics
+}, data passwork.PasswordResponseData) diag.Diagnostics {
+	value, err := json.Marshal(newRemoteVersion(data))
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Error Storing Password Version", "Could not encode the change marker of the password entry: "+err.Error())
+		return diags
+	}
+
+	return private.SetKey(ctx, remoteVersionKey, value)
+}
+
+// getRemoteVersion returns the stored change marker. It is nil for resources created or imported
+// with an older version of the provider, including markers of hashed values, which are not compared.
+func getRemoteVersion(ctx context.Context, private interface {
+	GetKey(context.Context, string) ([]byte, diag.Diagnostics)
+}) (*remoteVersion, diag.Diagnostics) {
+	value, diags := private.GetKey(ctx, remoteVersionKey)
+	if diags.HasError() || len(value) == 0 {
+		return nil, diags
+	}
+
+	var version remoteVersion
+	if err := json.Unmarshal(value, &version); err != nil {
+		diags.AddError("Error Reading Password Version", "Could not decode the change marker of the password entry: "+err.Error())
+		return nil, diags
+	}
+	if version.Values == nil {
+		return nil, diags
+	}
+
+	return &version, diags
+}
@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/lupa95/passwork-client-go"
+)
+
+func TestRemoteChanges(t *testing.T) {
+	data := passwork.PasswordResponseData{
+		Id:                 "password",
+		VaultId:            "vault",
+		Name:               "test",
+		Login:              "alice",
+		CryptedPassword:    "c2VjcmV0",
+		Tags:               []string{"a", "b"},
+		LastPasswordUpdate: 1700000000,
+	}
+	version := newRemoteVersion(data)
+
+	if changes := remoteChanges(version, data); len(changes) != 0 {
+		t.Fatalf("expected no changes, got %v", changes)
+	}
+
+	// Neither the password nor a hash of it is stored
+	marker, err := json.Marshal(version)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if strings.Contains(string(marker), data.CryptedPassword) || strings.Contains(string(marker), "secret") {
+		t.Fatalf("expected no password in the marker, got %s", marker)
+	}
+
+	// Fields not managed by the provider are ignored
+	data.IsFavorite = true
+	data.UpdatedAt = "2024-01-01T00:00:00Z"
+	if changes := remoteChanges(version, data); len(changes) != 0 {
+		t.Fatalf("expected no changes, got %v", changes)
+	}
+
+	data.Login = "bob"
+	data.CryptedPassword = "bmV3LXNlY3JldA=="
+	data.LastPasswordUpdate = 1700000100
+	changes := strings.Join(remoteChanges(version, data), "\n")
+	expected := "  ~ login changed remotely from \"alice\" to \"bob\"\n  ~ password changed remotely (value hidden)"
+	if changes != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, changes)
+	}
+}
+
+func TestGetRemoteVersionHashed(t *testing.T) {
+	// Markers of older versions contain hashes and are not compared
+	private := testPrivateState{remoteVersionKey: []byte(`{"fields":{"login":"2bd806c9"}}`)}
+	version, diags := getRemoteVersion(context.Background(), private)
+	if diags.HasError() || version != nil {
+		t.Fatalf("expected no version, got %v (%v)", version, diags)
+	}
+}
+
+// testPrivateState is a private state for tests.
+type testPrivateState map[string][]byte
+
+func (p testPrivateState) GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics) {
+	return p[key], nil
+}
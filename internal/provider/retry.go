@@ -0,0 +1,315 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lupa95/passwork-client-go"
+)
+
+// retryPolicy defines how often and how long to wait before transient API failures are retried.
+type retryPolicy struct {
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+// backoff returns the time to wait before the given retry attempt (starting at 0).
+// A Retry-After value sent by the server takes precedence.
+func (p retryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+
+	backoff := p.minBackoff
+	for i := 0; i < attempt && backoff < p.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > p.maxBackoff {
+		backoff = p.maxBackoff
+	}
+
+	return backoff
+}
+
+// transientError is returned for failed requests, which can be retried, e.g. if the
+// load balancer in front of Passwork responds with 502 during a deployment.
+type transientError struct {
+	StatusCode int
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *transientError) Error() string {
+	if e.Err != nil {
+		return "Passwork API request failed: " + e.Err.Error()
+	}
+
+	return fmt.Sprintf("Passwork API responded with %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+func (e *transientError) Unwrap() error {
+	return e.Err
+}
+
+func isTransientError(err error) (*transientError, bool) {
+	var transient *transientError
+	ok := errors.As(err, &transient)
+	return transient, ok
+}
+
+// retryTransport retries idempotent requests on transient failures. Non-idempotent requests
+// are never retried here, they fail with a transientError, so the caller can check if the
+// request was already processed before trying again.
+type retryTransport struct {
+	base   http.RoundTripper
+	policy retryPolicy
+	// timeout bounds each attempt instead of the whole request, so the backoff between
+	// attempts is not cut short. The http.Client using the transport must have no timeout.
+	timeout time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	idempotent := isIdempotent(req)
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.send(req, attempt)
+		if err == nil {
+			return resp, nil
+		}
+
+		transient, ok := isTransientError(err)
+		if !ok || !idempotent || attempt >= t.policy.maxRetries {
+			return nil, err
+		}
+
+		if err := sleep(req.Context(), t.policy.backoff(attempt, transient.RetryAfter)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// send executes a copy of the request and converts transient failures into a transientError.
+func (t *retryTransport) send(req *http.Request, attempt int) (*http.Response, error) {
+	ctx := req.Context()
+	if attempt > 0 {
+		ctx = withRetryAttempt(ctx, attempt)
+	}
+
+	cancel := context.CancelFunc(func() {})
+	if t.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, t.timeout)
+	}
+
+	clone := req.Clone(ctx)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		clone.Body = body
+	}
+
+	resp, err := t.base.RoundTrip(clone)
+	if err != nil {
+		cancel()
+		if req.Context().Err() != nil {
+			return nil, err
+		}
+		return nil, &transientError{Err: err}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		resp.Body.Close()
+		cancel()
+		return nil, &transientError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+
+	// The timeout of the attempt also bounds reading the body
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+
+	return resp, nil
+}
+
+// cancelBody releases the context of an attempt, when the response body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// isIdempotent reports if a request can be sent again without side effects.
+// Search endpoints of the v4 API use POST, but do not modify anything. A retried DELETE
+// may find the object deleted by the first attempt, so Delete treats not-found as success.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPatch:
+		// PATCH bodies of the v7 backend contain all managed fields, including cleared ones as null,
+		// so sending them again has the same result
+		return true
+	case http.MethodPost:
+		return strings.HasSuffix(req.URL.Path, "/search")
+	}
+
+	return false
+}
+
+// parseRetryAfter parses the Retry-After header, which is either a number of seconds or a HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+
+	return 0
+}
+
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// AddPassword creates a password entry. If the request fails with a transient error, the
+// password is only created again, if the vault does not contain a new entry with the same name.
+func (c *passworkClient) AddPassword(ctx context.Context, request passwork.PasswordRequest) (passwork.PasswordResponse, error) {
+	if c.retry.maxRetries == 0 {
+		return c.backend.AddPassword(ctx, request)
+	}
+
+	search := func() (map[string]bool, error) {
+		ids := map[string]bool{}
+		response, err := c.SearchPassword(ctx, passwork.PasswordSearchRequest{Query: request.Name, VaultId: request.VaultId})
+		if err != nil {
+			return ids, err
+		}
+		for _, password := range response.Data {
+			if password.Name == request.Name && password.FolderId == request.FolderId {
+				ids[password.Id] = true
+			}
+		}
+		return ids, nil
+	}
+
+	// Without knowing the existing entries, a retry could not detect duplicates
+	existing, err := search()
+	if err != nil {
+		return c.backend.AddPassword(ctx, request)
+	}
+
+	for attempt := 0; ; attempt++ {
+		response, err := c.backend.AddPassword(withRetryAttempt(ctx, attempt), request)
+		transient, ok := isTransientError(err)
+		if !ok || attempt >= c.retry.maxRetries {
+			return response, err
+		}
+
+		// Check if the password was created, although the request failed
+		current, searchErr := search()
+		if searchErr != nil {
+			return response, err
+		}
+		for id := range current {
+			if !existing[id] {
+				// Keep the Id, so the created password is not orphaned, if reading it fails
+				response, err := c.GetPassword(ctx, id)
+				response.Data.Id = id
+				return response, err
+			}
+		}
+
+		if err := sleep(ctx, c.retry.backoff(attempt, transient.RetryAfter)); err != nil {
+			return response, err
+		}
+	}
+}
+
+// AddFolder creates a folder. If the request fails with a transient error, the folder
+// is only created again, if the vault does not contain a new folder with the same name.
+func (c *passworkClient) AddFolder(ctx context.Context, request passwork.FolderRequest) (passwork.FolderResponse, error) {
+	if c.retry.maxRetries == 0 {
+		return c.backend.AddFolder(ctx, request)
+	}
+
+	search := func() (map[string]bool, error) {
+		ids := map[string]bool{}
+		response, err := c.SearchFolder(ctx, passwork.FolderSearchRequest{Query: request.Name, VaultId: request.VaultId})
+		if err != nil {
+			return ids, err
+		}
+		for _, folder := range response.Data {
+			if folder.Name == request.Name && folder.ParentId == request.ParentId {
+				ids[folder.Id] = true
+			}
+		}
+		return ids, nil
+	}
+
+	// Without knowing the existing entries, a retry could not detect duplicates
+	existing, err := search()
+	if err != nil {
+		return c.backend.AddFolder(ctx, request)
+	}
+
+	for attempt := 0; ; attempt++ {
+		response, err := c.backend.AddFolder(withRetryAttempt(ctx, attempt), request)
+		transient, ok := isTransientError(err)
+		if !ok || attempt >= c.retry.maxRetries {
+			return response, err
+		}
+
+		// Check if the folder was created, although the request failed
+		current, searchErr := search()
+		if searchErr != nil {
+			return response, err
+		}
+		for id := range current {
+			if !existing[id] {
+				// Keep the Id, so the created folder is not orphaned, if reading it fails
+				response, err := c.GetFolder(ctx, id)
+				response.Data.Id = id
+				return response, err
+			}
+		}
+
+		if err := sleep(ctx, c.retry.backoff(attempt, transient.RetryAfter)); err != nil {
+			return response, err
+		}
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lupa95/passwork-client-go"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := retryPolicy{maxRetries: 5, minBackoff: time.Second, maxBackoff: 5 * time.Second}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for attempt, want := range expected {
+		if got := policy.backoff(attempt, 0); got != want {
+			t.Errorf("attempt %d: expected %s, got %s", attempt, want, got)
+		}
+	}
+
+	if got := policy.backoff(0, 7*time.Second); got != 7*time.Second {
+		t.Errorf("expected Retry-After to take precedence, got %s", got)
+	}
+}
+
+func TestRetryTransport(t *testing.T) {
+	var gets, posts, searches int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/passwords/password", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&gets, 1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"status":"success","data":{"id":"password","name":"test"}}`)
+	})
+	mux.HandleFunc("/api/v4/passwords", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&posts, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	mux.HandleFunc("/api/v4/passwords/search", func(w http.ResponseWriter, r *http.Request) {
+		// The first create request was processed, although the gateway failed
+		if atomic.AddInt32(&searches, 1) == 1 {
+			fmt.Fprint(w, `{"status":"success","data":[]}`)
+			return
+		}
+		fmt.Fprint(w, `{"status":"success","data":[{"id":"password","name":"test"}]}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	policy := retryPolicy{maxRetries: 3, minBackoff: time.Millisecond, maxBackoff: time.Millisecond}
+	v4Client := passwork.NewClient(server.URL+"/api/v4", "test-key", 5*time.Second)
+	v4Client.HTTPClient.Transport = &retryTransport{base: http.DefaultTransport, policy: policy}
+	client := newPassworkClient(newV4Backend(v4Client), false, "")
+	client.retry = policy
+
+	response, err := client.GetPassword(context.Background(), "password")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if response.Data.Name != "test" || gets != 3 {
+		t.Fatalf("expected password after 3 requests, got %q after %d requests", response.Data.Name, gets)
+	}
+
+	response, err = client.AddPassword(context.Background(), passwork.PasswordRequest{Name: "test", VaultId: "vault"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if response.Data.Id != "password" {
+		t.Fatalf("expected created password to be found, got %q", response.Data.Id)
+	}
+	if posts != 1 {
+		t.Fatalf("expected create request to be sent once, got %d", posts)
+	}
+}
+
+func TestRetryTransportTimeout(t *testing.T) {
+	var attempts int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&attempts, 1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			// Exceeds the timeout of the attempt
+			select {
+			case <-time.After(time.Second):
+			case <-r.Context().Done():
+			}
+		default:
+			fmt.Fprint(w, `{"status":"success","data":{"id":"password","name":"test"}}`)
+		}
+	}))
+	defer server.Close()
+
+	// The attempts and the backoff between them take longer than the timeout of a single attempt
+	policy := retryPolicy{maxRetries: 3, minBackoff: 150 * time.Millisecond, maxBackoff: 150 * time.Millisecond}
+	v4Client := newV4Client(server.URL+"/api/v4", "test-key", http.DefaultTransport, policy, 100*time.Millisecond)
+	client := newPassworkClient(newV4Backend(v4Client), false, "")
+
+	start := time.Now()
+	response, err := client.GetPassword(context.Background(), "password")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if response.Data.Name != "test" || attempts != 3 {
+		t.Fatalf("expected password after 3 attempts, got %q after %d attempts", response.Data.Name, attempts)
+	}
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Fatalf("expected the backoff to be waited for, took %s", elapsed)
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lupa95/passwork-client-go"
+)
+
+// Error codes returned by the Passwork API, when the session token is expired or was invalidated.
+var sessionExpiredCodes = map[string]bool{
+	"invalidToken":   true,
+	"expiredToken":   true,
+	"tokenExpired":   true,
+	"unauthorized":   true,
+	"sessionExpired": true,
+}
+
+// sessionTransport keeps the Passwork session alive. It tracks the session token returned by the
+// login endpoint, logs in again if the session expires and replays the failed request with the new token.
+type sessionTransport struct {
+	base    http.RoundTripper
+	baseURL string
+	apiKey  string
+
+	mutex sync.RWMutex
+	token string
+	// generation is increased with every login, so concurrent requests failing
+	// with the same expired token only trigger a single login.
+	generation int
+}
+
+func newSessionTransport(base http.RoundTripper, baseURL, apiKey string) *sessionTransport {
+	return &sessionTransport{
+		base:    base,
+		baseURL: baseURL,
+		apiKey:  apiKey,
+	}
+}
+
+func (t *sessionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.isLoginRequest(req) {
+		return t.login(req)
+	}
+
+	t.mutex.RLock()
+	token, generation := t.token, t.generation
+	t.mutex.RUnlock()
+
+	resp, err := t.send(req, token)
+	if err != nil || t.isLogoutRequest(req) || !isSessionExpired(resp) {
+		return resp, err
+	}
+
+	// Session expired: login again and replay the request once
+	resp.Body.Close()
+	if err := t.renew(req, generation); err != nil {
+		return nil, fmt.Errorf("session expired and renewing it failed: %w", err)
+	}
+
+	if req.Body != nil && req.GetBody == nil {
+		return nil, errors.New("session expired and request can not be replayed")
+	}
+
+	t.mutex.RLock()
+	token = t.token
+	t.mutex.RUnlock()
+
+	return t.send(req, token)
+}
+
+// send executes a copy of the request authenticated with the given token.
+func (t *sessionTransport) send(req *http.Request, token string) (*http.Response, error) {
+	clone := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		clone.Body = body
+	}
+
+	if token != "" {
+		clone.Header.Set("Passwork-Auth", token)
+	}
+
+	return t.base.RoundTrip(clone)
+}
+
+// login forwards a login request and remembers the returned session token.
+func (t *sessionTransport) login(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	body, err := readBody(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var login passwork.LoginResponse
+	if json.Unmarshal(body, &login) == nil && login.Status == "success" {
+		t.mutex.Lock()
+		t.token = login.Data.Token
+		t.generation++
+		t.mutex.Unlock()
+	}
+
+	return resp, nil
+}
+
+// renew logs in again, unless another request already renewed the session since generation.
+func (t *sessionTransport) renew(req *http.Request, generation int) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.generation != generation {
+		return nil
+	}
+
+	login, err := http.NewRequestWithContext(req.Context(), http.MethodPost, fmt.Sprintf("%s/auth/login/%s", t.baseURL, t.apiKey), nil)
+	if err != nil {
+		return err
+	}
+	login.Header.Set("Accept", "application/json")
+	login.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.base.RoundTrip(login)
+	if err != nil {
+		return err
+	}
+
+	body, err := readBody(resp)
+	if err != nil {
+		return err
+	}
+
+	var response passwork.LoginResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("failed to parse login response: %w", err)
+	}
+	if response.Status != "success" {
+		return fmt.Errorf("login failed, status: %s", response.Status)
+	}
+
+	t.token = response.Data.Token
+	t.generation++
+
+	return nil
+}
+
+func (t *sessionTransport) isLoginRequest(req *http.Request) bool {
+	return strings.HasPrefix(req.URL.String(), t.baseURL+"/auth/login/")
+}
+
+func (t *sessionTransport) isLogoutRequest(req *http.Request) bool {
+	return req.URL.String() == t.baseURL+"/auth/logout"
+}
+
+// isSessionExpired checks if the API rejected the request because of an invalid session token.
+func isSessionExpired(resp *http.Response) bool {
+	if resp.StatusCode == http.StatusUnauthorized {
+		return true
+	}
+	if resp.StatusCode < http.StatusBadRequest {
+		return false
+	}
+
+	body, err := readBody(resp)
+	if err != nil {
+		return false
+	}
+
+	var response struct {
+		Code string
+	}
+	return json.Unmarshal(body, &response) == nil && sessionExpiredCodes[response.Code]
+}
+
+// readBody reads the response body and replaces it, so it can be read again by the caller.
+func readBody(resp *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// sessions holds all clients with an open Passwork session, so they can be logged out on shutdown.
+var sessions struct {
+	mutex   sync.Mutex
+	clients []*passworkClient
+}
+
+func registerSession(client *passworkClient) {
+	sessions.mutex.Lock()
+	defer sessions.mutex.Unlock()
+
+	sessions.clients = append(sessions.clients, client)
+}
+
+// logoutTimeout limits how long logging out may delay the shutdown. Terraform kills the provider
+// process about two seconds after it asked it to stop.
+const logoutTimeout = time.Second
+
+// logoutSessions ends the Passwork sessions of all configured providers.
+func logoutSessions(ctx context.Context) {
+	sessions.mutex.Lock()
+	defer sessions.mutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, logoutTimeout)
+	defer cancel()
+
+	for _, client := range sessions.clients {
+		// Best effort, the session expires on its own if logging out fails
+		_ = client.Logout(ctx)
+	}
+	sessions.clients = nil
+}
@@ -0,0 +1,91 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lupa95/passwork-client-go"
+)
+
+func TestSessionTransportRenewsExpiredSession(t *testing.T) {
+	var (
+		logins int32
+		token  atomic.Value
+	)
+	token.Store("")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/auth/login/test-key", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&logins, 1)
+		token.Store(fmt.Sprintf("token-%d", n))
+		fmt.Fprintf(w, `{"status":"success","data":{"token":"token-%d"}}`, n)
+	})
+	mux.HandleFunc("/api/v4/folders/folder", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Passwork-Auth") != token.Load().(string) {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"status":"error","code":"invalidToken"}`)
+			return
+		}
+		fmt.Fprint(w, `{"status":"success","data":{"id":"folder","name":"test"}}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	url := server.URL + "/api/v4"
+	client := passwork.NewClient(url, "test-key", 5*time.Second)
+	client.HTTPClient.Transport = newSessionTransport(http.DefaultTransport, url, "test-key")
+	if err := client.Login(); err != nil {
+		t.Fatalf("unexpected login error: %s", err)
+	}
+
+	// Invalidate the session on the server side
+	token.Store("invalidated")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			response, err := client.GetFolder("folder")
+			if err != nil {
+				t.Errorf("unexpected error: %s", err)
+				return
+			}
+			if response.Data.Name != "test" {
+				t.Errorf("unexpected folder name: %s", response.Data.Name)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&logins); n != 2 {
+		t.Fatalf("expected exactly one renewal login, got %d logins", n)
+	}
+}
+
+func TestShutdownLogoutTimeout(t *testing.T) {
+	// The server never answers the logout request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	registerSession(newPassworkClient(newV4Backend(passwork.NewClient(server.URL+"/api/v4", "test-key", time.Minute)), false, ""))
+
+	start := time.Now()
+	if err := Shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if elapsed := time.Since(start); elapsed > logoutTimeout+time.Second {
+		t.Fatalf("expected the logout to time out after %s, took %s", logoutTimeout, elapsed)
+	}
+}
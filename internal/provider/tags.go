@@ -0,0 +1,162 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	_ basetypes.SetTypable                    = tagsType{}
+	_ basetypes.SetValuableWithSemanticEquals = tagsValue{}
+)
+
+// normalizeTags trims the tags and removes empty tags and tags, which only differ in case
+// from a previous tag. The tags are sorted, so they are sent and compared in a stable order.
+func normalizeTags(tags []string) []string {
+	var normalized []string
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, tag)
+	}
+
+	sort.Slice(normalized, func(i, j int) bool {
+		return strings.ToLower(normalized[i]) < strings.ToLower(normalized[j])
+	})
+
+	return normalized
+}
+
+// tagsType is the type of the tags of a password entry. Tags returned by Passwork are
+// semantically equal to the configured tags, if they only differ after normalization,
+// so the order, whitespace or case of the tags in Passwork does not cause a diff.
+type tagsType struct {
+	basetypes.SetType
+}
+
+func newTagsType() tagsType {
+	return tagsType{SetType: basetypes.SetType{ElemType: types.StringType}}
+}
+
+func (t tagsType) Equal(o attr.Type) bool {
+	other, ok := o.(tagsType)
+	if !ok {
+		return false
+	}
+
+	return t.SetType.Equal(other.SetType)
+}
+
+func (t tagsType) String() string {
+	return "tagsType"
+}
+
+func (t tagsType) ValueFromSet(ctx context.Context, in basetypes.SetValue) (basetypes.SetValuable, diag.Diagnostics) {
+	return tagsValue{SetValue: in}, nil
+}
+
+func (t tagsType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	value, err := t.SetType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	setValue, ok := value.(basetypes.SetValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", value)
+	}
+
+	return tagsValue{SetValue: setValue}, nil
+}
+
+func (t tagsType) ValueType(ctx context.Context) attr.Value {
+	return tagsValue{}
+}
+
+// tagsValue is the value of the tags of a password entry.
+type tagsValue struct {
+	basetypes.SetValue
+}
+
+// newTagsValue returns the normalized tags. No tags are returned as null, as Passwork does not
+// distinguish them from an empty set.
+func newTagsValue(tags []string) tagsValue {
+	tags = normalizeTags(tags)
+	if len(tags) == 0 {
+		return tagsValue{SetValue: basetypes.NewSetNull(types.StringType)}
+	}
+
+	elements := make([]attr.Value, 0, len(tags))
+	for _, tag := range tags {
+		elements = append(elements, types.StringValue(tag))
+	}
+
+	return tagsValue{SetValue: basetypes.NewSetValueMust(types.StringType, elements)}
+}
+
+func (v tagsValue) Equal(o attr.Value) bool {
+	other, ok := o.(tagsValue)
+	if !ok {
+		return false
+	}
+
+	return v.SetValue.Equal(other.SetValue)
+}
+
+func (v tagsValue) Type(ctx context.Context) attr.Type {
+	return newTagsType()
+}
+
+// SetSemanticEquals reports whether the tags are equal after normalization, ignoring the case.
+func (v tagsValue) SetSemanticEquals(ctx context.Context, newValuable basetypes.SetValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(tagsValue)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T, got: %T. Please report this issue to the provider developers.", v, newValuable),
+		)
+		return false, diags
+	}
+
+	current, updated := normalizeTags(v.Strings()), normalizeTags(newValue.Strings())
+	if len(current) != len(updated) {
+		return false, diags
+	}
+	for i := range current {
+		if !strings.EqualFold(current[i], updated[i]) {
+			return false, diags
+		}
+	}
+
+	return true, diags
+}
+
+// Strings returns the known tags.
+func (v tagsValue) Strings() []string {
+	var tags []string
+	for _, element := range v.Elements() {
+		if tag, ok := element.(types.String); ok && !tag.IsNull() && !tag.IsUnknown() {
+			tags = append(tags, tag.ValueString())
+		}
+	}
+
+	return tags
+}
@@ -0,0 +1,91 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tags := normalizeTags([]string{" test", "Provider", "", "tag ", "provider", "TEST"})
+	if strings.Join(tags, ",") != "Provider,tag,test" {
+		t.Fatalf("unexpected tags %q", tags)
+	}
+
+	if tags := normalizeTags([]string{" ", ""}); tags != nil {
+		t.Fatalf("expected no tags, got %q", tags)
+	}
+}
+
+func TestTagsSemanticEquals(t *testing.T) {
+	ctx := context.Background()
+	configured := newTagsValue([]string{"provider", "test"})
+
+	testCases := map[string]struct {
+		tags  []string
+		equal bool
+	}{
+		"other order": {tags: []string{"test", "provider"}, equal: true},
+		"whitespace":  {tags: []string{" provider", "test "}, equal: true},
+		"case":        {tags: []string{"Provider", "TEST"}, equal: true},
+		"added":       {tags: []string{"provider", "test", "tag"}},
+		"changed":     {tags: []string{"provider", "changed"}},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			equal, diags := configured.SetSemanticEquals(ctx, newTagsValue(testCase.tags))
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			if equal != testCase.equal {
+				t.Fatalf("expected equal %t, got %t", testCase.equal, equal)
+			}
+		})
+	}
+}
+
+func TestUpgradePasswordStateV0(t *testing.T) {
+	ctx := context.Background()
+	r := &PasswordResource{}
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	upgrader := r.UpgradeState(ctx)[0]
+	priorType := upgrader.PriorSchema.Type().TerraformType(ctx).(tftypes.Object)
+
+	values := map[string]tftypes.Value{}
+	for name, attributeType := range priorType.AttributeTypes {
+		values[name] = tftypes.NewValue(attributeType, nil)
+	}
+	values["id"] = tftypes.NewValue(tftypes.String, "abc")
+	values["tags"] = tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+		tftypes.NewValue(tftypes.String, "test"),
+		tftypes.NewValue(tftypes.String, "provider"),
+		tftypes.NewValue(tftypes.String, "test"),
+	})
+
+	req := resource.UpgradeStateRequest{State: &tfsdk.State{Schema: *upgrader.PriorSchema, Raw: tftypes.NewValue(priorType, values)}}
+	resp := resource.UpgradeStateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+	upgrader.StateUpgrader(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var state PasswordResourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	if state.Id.ValueString() != "abc" || strings.Join(state.Tags.Strings(), ",") != "provider,test" {
+		t.Fatalf("unexpected state %+v", state)
+	}
+}
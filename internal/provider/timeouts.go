@@ -0,0 +1,39 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+// Default timeouts of resource operations, which can be changed in the timeouts block of
+// each resource. Deletes can take longer, as vaults and folders are deleted with their contents.
+const (
+	defaultCreateTimeout = 10 * time.Minute
+	defaultReadTimeout   = 2 * time.Minute
+	defaultUpdateTimeout = 10 * time.Minute
+	defaultDeleteTimeout = 20 * time.Minute
+)
+
+// timeoutsBlock returns the timeouts block of the resources, whose timeouts bound the
+// whole operation including retries and follow-up requests.
+func timeoutsBlock(ctx context.Context) schema.Block {
+	description := "A duration like `30s` or `2h45m`, which bounds the whole %s operation including retries and follow-up requests. Defaults to `%s`."
+
+	return timeouts.Block(ctx, timeouts.Opts{
+		Create:            true,
+		Read:              true,
+		Update:            true,
+		Delete:            true,
+		CreateDescription: fmt.Sprintf(description, "create", defaultCreateTimeout),
+		ReadDescription:   fmt.Sprintf(description, "read", defaultReadTimeout),
+		UpdateDescription: fmt.Sprintf(description, "update", defaultUpdateTimeout),
+		DeleteDescription: fmt.Sprintf(description, "delete", defaultDeleteTimeout),
+	})
+}
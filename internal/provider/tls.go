@@ -0,0 +1,67 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// tlsSettings holds the TLS configuration of the connection to the Passwork API.
+type tlsSettings struct {
+	caCertFile         string
+	caCertPem          string
+	clientCertFile     string
+	clientKeyFile      string
+	insecureSkipVerify bool
+}
+
+// newTLSConfig builds the TLS client configuration. Custom CA certificates are added to the system pool.
+func newTLSConfig(settings tlsSettings) (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// Explicitly requested by the practitioner, e.g. for test instances with self-signed certificates
+		InsecureSkipVerify: settings.insecureSkipVerify,
+	}
+
+	if settings.caCertFile != "" || settings.caCertPem != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+
+		if settings.caCertFile != "" {
+			pem, err := os.ReadFile(settings.caCertFile)
+			if err != nil {
+				return nil, fmt.Errorf("could not read CA certificate file: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("CA certificate file %s does not contain a PEM encoded certificate", settings.caCertFile)
+			}
+		}
+
+		if settings.caCertPem != "" && !pool.AppendCertsFromPEM([]byte(settings.caCertPem)) {
+			return nil, errors.New("CA certificate does not contain a PEM encoded certificate")
+		}
+
+		config.RootCAs = pool
+	}
+
+	if settings.clientCertFile != "" || settings.clientKeyFile != "" {
+		if settings.clientCertFile == "" || settings.clientKeyFile == "" {
+			return nil, errors.New("client certificate and client key must be set together")
+		}
+
+		certificate, err := tls.LoadX509KeyPair(settings.clientCertFile, settings.clientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+
+	return config, nil
+}
@@ -0,0 +1,216 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName = "terraform-provider-passwork"
+	// tracesFileEnv is the environment variable for a file, to which spans are written as JSON.
+	tracesFileEnv = "PASSWORK_OTEL_TRACES_FILE"
+	// tracesShutdownTimeout limits how long exporting the remaining spans may delay the shutdown.
+	// Terraform kills the provider process about two seconds after it asked it to stop.
+	tracesShutdownTimeout = 1500 * time.Millisecond
+	// tracesBatchTimeout is the interval, in which finished spans are exported, so only few
+	// spans are left to export, when the provider shuts down.
+	tracesBatchTimeout = time.Second
+)
+
+// tracerProvider is set, if exporting traces is enabled. Otherwise all spans are discarded.
+var tracerProvider *sdktrace.TracerProvider
+
+// InitTracing enables the export of OpenTelemetry spans, if an OTLP endpoint is set with the
+// standard OTEL_EXPORTER_OTLP_ENDPOINT or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT environment variables,
+// or a file is set with PASSWORK_OTEL_TRACES_FILE. Spans are never written to stdout,
+// as it is used for the communication with Terraform.
+func InitTracing(ctx context.Context, version string) error {
+	var exporters []sdktrace.SpanExporter
+
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != "" {
+		exporter, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+		}
+		exporters = append(exporters, exporter)
+	}
+
+	if file := os.Getenv(tracesFileEnv); file != "" {
+		writer, err := os.OpenFile(file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", tracesFileEnv, err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(writer))
+		if err != nil {
+			writer.Close()
+			return fmt.Errorf("failed to create file trace exporter: %w", err)
+		}
+		exporters = append(exporters, &closingExporter{SpanExporter: exporter, file: writer})
+	}
+
+	if len(exporters) == 0 {
+		return nil
+	}
+
+	options := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewSchemaless(
+			semconv.ServiceName(tracerName),
+			semconv.ServiceVersion(version),
+		)),
+	}
+	for _, exporter := range exporters {
+		options = append(options, sdktrace.WithBatcher(exporter, sdktrace.WithBatchTimeout(tracesBatchTimeout)))
+	}
+
+	tracerProvider = sdktrace.NewTracerProvider(options...)
+	otel.SetTracerProvider(tracerProvider)
+
+	return nil
+}
+
+// shutdownTracing exports all remaining spans.
+func shutdownTracing(ctx context.Context) error {
+	if tracerProvider == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, tracesShutdownTimeout)
+	defer cancel()
+
+	return tracerProvider.Shutdown(ctx)
+}
+
+// closingExporter closes the traces file after the exporter is shut down.
+type closingExporter struct {
+	sdktrace.SpanExporter
+	file *os.File
+}
+
+func (e *closingExporter) Shutdown(ctx context.Context) error {
+	return errors.Join(e.SpanExporter.Shutdown(ctx), e.file.Close())
+}
+
+// startSpan starts the span of a resource or data source operation, e.g. PasswordResource.Create.
+// The returned context counts the retries of all API calls of the operation.
+func startSpan(ctx context.Context, name, resourceType string) (context.Context, trace.Span) {
+	ctx = context.WithValue(ctx, retryCountKey{}, new(atomic.Int64))
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(
+		attribute.String("passwork.resource_type", resourceType),
+	))
+}
+
+// endSpan adds the number of retries of the operation to the span, marks the span as failed,
+// if the operation returned errors, and ends it.
+func endSpan(ctx context.Context, span trace.Span, diags diag.Diagnostics) {
+	if retries, ok := ctx.Value(retryCountKey{}).(*atomic.Int64); ok {
+		span.SetAttributes(attribute.Int64("passwork.retry_count", retries.Load()))
+	}
+
+	if diags.HasError() {
+		errs := diags.Errors()
+		span.SetStatus(codes.Error, errs[0].Summary())
+		for _, d := range errs {
+			span.AddEvent("error", trace.WithAttributes(
+				attribute.String("summary", d.Summary()),
+				attribute.String("detail", d.Detail()),
+			))
+		}
+	}
+
+	span.End()
+}
+
+// retryAttemptKey is the context key for the number of the current retry attempt.
+type retryAttemptKey struct{}
+
+// retryCountKey is the context key for the number of retries of an operation.
+type retryCountKey struct{}
+
+// withRetryAttempt returns a context for the given attempt of an API call. Retries are
+// counted for the span of the operation.
+func withRetryAttempt(ctx context.Context, attempt int) context.Context {
+	if retries, ok := ctx.Value(retryCountKey{}).(*atomic.Int64); ok && attempt > 0 {
+		retries.Add(1)
+	}
+
+	return context.WithValue(ctx, retryAttemptKey{}, attempt)
+}
+
+func retryAttempt(ctx context.Context) int {
+	attempt, _ := ctx.Value(retryAttemptKey{}).(int)
+	return attempt
+}
+
+// tracingTransport creates a span for every Passwork API call. Secrets, e.g. the
+// API key which is part of the login URL, are masked in the recorded path.
+type tracingTransport struct {
+	base    http.RoundTripper
+	secrets []string
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := otel.Tracer(tracerName).Start(req.Context(), "Passwork API "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.URLPath(t.mask(req.URL.Path)),
+			semconv.ServerAddress(req.URL.Hostname()),
+			attribute.Int("passwork.retry_count", retryAttempt(req.Context())),
+		),
+	)
+	defer span.End()
+
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+
+	return resp, nil
+}
+
+func (t *tracingTransport) mask(value string) string {
+	for _, secret := range t.secrets {
+		if secret != "" {
+			value = strings.ReplaceAll(value, secret, "***")
+		}
+	}
+
+	return value
+}
+
+// setSpanAttributes adds the IDs of the Passwork objects, e.g. id and vault_id, to the span.
+// Unknown IDs, e.g. the id of a password to be created, are empty and not added.
+func setSpanAttributes(span trace.Span, ids map[string]string) {
+	for key, value := range ids {
+		if value != "" {
+			span.SetAttributes(attribute.String("passwork."+key, value))
+		}
+	}
+}
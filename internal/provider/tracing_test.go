@@ -0,0 +1,82 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingTransport(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	defer otel.SetTracerProvider(previous)
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &retryTransport{
+		base:   &tracingTransport{base: http.DefaultTransport, secrets: []string{"test-key"}},
+		policy: retryPolicy{maxRetries: 1, minBackoff: time.Millisecond, maxBackoff: time.Millisecond},
+	}}
+
+	ctx, span := startSpan(context.Background(), "PasswordResource.Read", "passwork_password")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v4/auth/login/test-key", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp.Body.Close()
+	endSpan(ctx, span, nil)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+
+	for attempt, apiSpan := range spans[:2] {
+		if apiSpan.Parent.SpanID() != span.SpanContext().SpanID() {
+			t.Errorf("expected API call %d to be a child of the operation span", attempt)
+		}
+
+		attributes := map[attribute.Key]attribute.Value{}
+		for _, kv := range apiSpan.Attributes {
+			attributes[kv.Key] = kv.Value
+		}
+		if path := attributes["url.path"].AsString(); path != "/api/v4/auth/login/***" {
+			t.Errorf("expected API key to be masked, got path %q", path)
+		}
+		if retries := attributes["passwork.retry_count"].AsInt64(); retries != int64(attempt) {
+			t.Errorf("expected retry count %d, got %d", attempt, retries)
+		}
+	}
+
+	attributes := map[attribute.Key]attribute.Value{}
+	for _, kv := range spans[2].Attributes {
+		attributes[kv.Key] = kv.Value
+	}
+	if retries, ok := attributes["passwork.retry_count"]; !ok || retries.AsInt64() != 1 {
+		t.Errorf("expected the operation span to count 1 retry, got %v", retries.Emit())
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+)
+
+// newHTTPTransport creates the transport, which sends the requests of the Passwork client.
+// Without a proxy URL, the proxy is taken from the HTTPS_PROXY and NO_PROXY environment variables.
+func newHTTPTransport(tlsConfig *tls.Config, proxyURL *url.URL) *http.Transport {
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if defaultTransport, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport = defaultTransport.Clone()
+	}
+
+	transport.TLSClientConfig = tlsConfig
+	if proxyURL != nil {
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	return transport
+}
+
+// headerTransport adds static headers to every request, e.g. for access gateways in front of Passwork.
+type headerTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(t.headers) == 0 {
+		return t.base.RoundTrip(req)
+	}
+
+	clone := req.Clone(req.Context())
+	for name, value := range t.headers {
+		clone.Header.Set(name, value)
+	}
+
+	return t.base.RoundTrip(clone)
+}
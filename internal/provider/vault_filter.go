@@ -0,0 +1,121 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lupa95/passwork-client-go"
+)
+
+// vaultFilter limits the vaults a provider configuration may access. Denied vaults take
+// precedence. If any allowed vaults are set, all other vaults are denied.
+type vaultFilter struct {
+	allowedIds   map[string]bool
+	deniedIds    map[string]bool
+	allowedNames map[string]bool
+	deniedNames  map[string]bool
+}
+
+func (f vaultFilter) enabled() bool {
+	return len(f.allowedIds)+len(f.deniedIds)+len(f.allowedNames)+len(f.deniedNames) > 0
+}
+
+// needsName reports if the name of a vault is required to check it.
+func (f vaultFilter) needsName() bool {
+	return len(f.allowedNames)+len(f.deniedNames) > 0
+}
+
+// allows checks the vault with the given Id and name. The Id is empty for vaults to be created.
+func (f vaultFilter) allows(id, name string) bool {
+	if (id != "" && f.deniedIds[id]) || f.deniedNames[name] {
+		return false
+	}
+
+	if len(f.allowedIds)+len(f.allowedNames) == 0 {
+		return true
+	}
+
+	return (id != "" && f.allowedIds[id]) || f.allowedNames[name]
+}
+
+// vaultNotAllowedError is returned, if the provider configuration must not access a vault.
+type vaultNotAllowedError struct {
+	id   string
+	name string
+}
+
+func (e *vaultNotAllowedError) Error() string {
+	vault := fmt.Sprintf("with ID %q", e.id)
+	switch {
+	case e.id == "":
+		vault = fmt.Sprintf("%q", e.name)
+	case e.name != "":
+		vault = fmt.Sprintf("%q with ID %q", e.name, e.id)
+	}
+
+	return "The vault " + vault + " is not allowed by the allowed_vault_ids, denied_vault_ids, allowed_vault_names and denied_vault_names provider attributes. " +
+		"Check that the vault is correct or allow it in the provider configuration."
+}
+
+// checkVault returns a vaultNotAllowedError, if the vault must not be accessed.
+// The name of the vault is read from the API, if vaults are allowed or denied by name.
+func (c *passworkClient) checkVault(ctx context.Context, vaultId string) error {
+	if !c.vaultFilter.enabled() || vaultId == "" {
+		return nil
+	}
+
+	var name string
+	if c.vaultFilter.needsName() {
+		var err error
+		name, err = c.vaultName(ctx, vaultId)
+		if err != nil {
+			return fmt.Errorf("could not read the name of vault %s to check if it is allowed: %w", vaultId, err)
+		}
+	}
+
+	if !c.vaultFilter.allows(vaultId, name) {
+		return &vaultNotAllowedError{id: vaultId, name: name}
+	}
+
+	return nil
+}
+
+// checkVaultName returns a vaultNotAllowedError, if a vault must not be created or renamed
+// with the given name. The Id is empty for vaults to be created.
+func (c *passworkClient) checkVaultName(vaultId, name string) error {
+	if !c.vaultFilter.allows(vaultId, name) {
+		return &vaultNotAllowedError{id: vaultId, name: name}
+	}
+
+	return nil
+}
+
+func (c *passworkClient) vaultName(ctx context.Context, vaultId string) (string, error) {
+	vault, err := c.cachedVault(ctx, vaultId)
+	return vault.Name, err
+}
+
+// allowedPasswords returns the passwords found by a search, which are in allowed vaults, and the
+// number of passwords, which were skipped, as their vault is not allowed.
+func (c *passworkClient) allowedPasswords(ctx context.Context, passwords []passwork.PasswordResponseData) ([]passwork.PasswordResponseData, int, error) {
+	var allowed []passwork.PasswordResponseData
+	skipped := 0
+	for _, password := range passwords {
+		var notAllowed *vaultNotAllowedError
+		err := c.checkVault(ctx, password.VaultId)
+		switch {
+		case errors.As(err, &notAllowed):
+			skipped++
+		case err != nil:
+			return nil, 0, err
+		default:
+			allowed = append(allowed, password)
+		}
+	}
+
+	return allowed, skipped, nil
+}
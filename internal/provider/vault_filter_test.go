@@ -0,0 +1,92 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lupa95/passwork-client-go"
+)
+
+func TestVaultFilterAllows(t *testing.T) {
+	testCases := map[string]struct {
+		filter   vaultFilter
+		id       string
+		name     string
+		expected bool
+	}{
+		"no filter":             {filter: vaultFilter{}, id: "vault", name: "Team", expected: true},
+		"allowed id":            {filter: vaultFilter{allowedIds: map[string]bool{"vault": true}}, id: "vault", expected: true},
+		"other id":              {filter: vaultFilter{allowedIds: map[string]bool{"vault": true}}, id: "other", expected: false},
+		"allowed name":          {filter: vaultFilter{allowedNames: map[string]bool{"Team": true}}, id: "other", name: "Team", expected: true},
+		"denied id":             {filter: vaultFilter{deniedIds: map[string]bool{"vault": true}}, id: "vault", expected: false},
+		"denied name":           {filter: vaultFilter{deniedNames: map[string]bool{"Team": true}}, id: "vault", name: "Team", expected: false},
+		"denied over allowed":   {filter: vaultFilter{allowedIds: map[string]bool{"vault": true}, deniedNames: map[string]bool{"Team": true}}, id: "vault", name: "Team", expected: false},
+		"new vault by id":       {filter: vaultFilter{allowedIds: map[string]bool{"vault": true}}, name: "Team", expected: false},
+		"new vault by name":     {filter: vaultFilter{allowedNames: map[string]bool{"Team": true}}, name: "Team", expected: true},
+		"new vault not denied":  {filter: vaultFilter{deniedIds: map[string]bool{"vault": true}}, name: "Team", expected: true},
+		"new vault denied name": {filter: vaultFilter{deniedNames: map[string]bool{"Team": true}}, name: "Team", expected: false},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if allowed := testCase.filter.allows(testCase.id, testCase.name); allowed != testCase.expected {
+				t.Fatalf("expected %t, got %t", testCase.expected, allowed)
+			}
+		})
+	}
+}
+
+func TestCheckVault(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		id := strings.TrimPrefix(r.URL.Path, "/api/v4/vaults/")
+		fmt.Fprintf(w, `{"status":"success","data":{"id":%q,"name":"Vault %s"}}`, id, id)
+	}))
+	defer server.Close()
+
+	client := newPassworkClient(newV4Backend(passwork.NewClient(server.URL+"/api/v4", "test-key", 5*time.Second)), false, "")
+	client.vaultFilter = vaultFilter{allowedNames: map[string]bool{"Vault team": true}}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := client.checkVault(ctx, "team"); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Fatalf("expected the vault name to be cached, got %d requests", n)
+	}
+
+	var notAllowed *vaultNotAllowedError
+	if err := client.checkVault(ctx, "other"); !errors.As(err, &notAllowed) {
+		t.Fatalf("expected vaultNotAllowedError, got %v", err)
+	}
+}
+
+func TestAllowedPasswords(t *testing.T) {
+	client := newPassworkClient(nil, false, "")
+	client.vaultFilter = vaultFilter{allowedIds: map[string]bool{"team": true}}
+
+	passwords, skipped, err := client.allowedPasswords(context.Background(), []passwork.PasswordResponseData{
+		{Id: "p1", VaultId: "other"},
+		{Id: "p2", VaultId: "team"},
+		{Id: "p3", VaultId: "other"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(passwords) != 1 || passwords[0].Id != "p2" || skipped != 2 {
+		t.Fatalf("expected only p2 to be allowed and 2 passwords to be skipped, got %+v and %d", passwords, skipped)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -24,6 +25,7 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &VaultResource{}
 var _ resource.ResourceWithImportState = &VaultResource{}
+var _ resource.ResourceWithModifyPlan = &VaultResource{}
 
 func NewVaultResource() resource.Resource {
 	return &VaultResource{}
@@ -31,7 +33,7 @@ func NewVaultResource() resource.Resource {
 
 // ExampleResource defines the resource implementation.
 type VaultResource struct {
-	client *passwork.Client
+	client *passworkClient
 }
 
 func (r *VaultResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -40,8 +42,20 @@ func (r *VaultResource) Metadata(ctx context.Context, req resource.MetadataReque
 
 func (r *VaultResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Use this resource to create a vault. Vaults are top level containers, that contain password entries.",
+		Description: "Use this resource to create a vault. Vaults are top level containers, that contain password entries. With client-side encryption, vaults cannot be created and must be imported.",
 		Attributes: map[string]schema.Attribute{
+			"force_destroy": schema.BoolAttribute{
+				Description: "Enable to delete all folders and passwords inside the vault, when the vault is destroyed. Otherwise a vault, which is not empty, is not deleted. The value must be applied, before the vault is destroyed. Each deleted object is logged at info level, which is shown with `TF_LOG=info`, and a failed deletion lists the objects, which were already deleted. Defaults to `false`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"deletion_protection": schema.BoolAttribute{
+				Description: "Prevents the vault from being deleted, while set to `true`. Set it to `false` and apply the change, before the vault can be destroyed or removed from the configuration. Defaults to `false`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
 			"name": schema.StringAttribute{
 				Description: "The name of the vault.",
 				Required:    true,
@@ -75,7 +89,7 @@ func (r *VaultResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				PlanModifiers: []planmodifier.Bool{boolplanmodifier.UseStateForUnknown()},
 			},
 			"master_password": schema.StringAttribute{
-				Description: "The master password of the vault.",
+				Description: "The master password of the vault. Not supported with the Passwork 7 API, where it is always null.",
 				Optional:    true,
 				Computed:    true,
 				Sensitive:   true,
@@ -84,6 +98,9 @@ func (r *VaultResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx),
+		},
 	}
 }
 
@@ -93,11 +110,11 @@ func (r *VaultResource) Configure(ctx context.Context, req resource.ConfigureReq
 		return
 	}
 
-	client, ok := req.ProviderData.(*passwork.Client)
+	client, ok := req.ProviderData.(*passworkClient)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *passwork.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *passworkClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -107,6 +124,9 @@ func (r *VaultResource) Configure(ctx context.Context, req resource.ConfigureReq
 }
 
 func (r *VaultResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startSpan(ctx, "VaultResource.Create", "passwork_vault")
+	defer func() { endSpan(ctx, span, resp.Diagnostics) }()
+
 	var (
 		plan         VaultResourceModel
 		newState     VaultResourceModel
@@ -123,6 +143,15 @@ func (r *VaultResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
+	// Bound the whole operation including retries and follow-up requests
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	// Build request
 	request.Name = plan.Name.ValueString()
 	request.IsPrivate = plan.IsPrivate.ValueBool()
@@ -131,45 +160,59 @@ func (r *VaultResource) Create(ctx context.Context, req resource.CreateRequest,
 	request.Salt = randomString(12)
 	request.PasswordHash = base64.StdEncoding.EncodeToString([]byte(randomString(12)))
 
+	masterPassword := plan.MasterPassword.ValueString()
 	if plan.MasterPassword.IsUnknown() {
-		mp := randomString(12)
-		request.MpCrypted = base64.StdEncoding.EncodeToString([]byte(mp))
-		newState.MasterPassword = types.StringValue(mp)
-	} else {
-		request.MpCrypted = base64.StdEncoding.EncodeToString([]byte(plan.MasterPassword.ValueString()))
-		newState.MasterPassword = plan.MasterPassword
+		masterPassword = randomString(12)
 	}
 
-	// Send create request
-	response_add, err = r.client.AddVault(request)
+	// Vaults are not created with client-side encryption, see checkPlannedVaultEncryption
+	request.MpCrypted, err = r.client.encryptVaultPassword(masterPassword)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Creating Vault",
-			"Could not create Vault, unexpected error: "+err.Error(),
+			"Could not encrypt Vault master password, unexpected error: "+err.Error(),
 		)
 		return
 	}
+	// Send create request
+	response_add, err = r.client.AddVault(ctx, request)
+	setSpanAttributes(span, map[string]string{"id": response_add.Data, "vault_id": response_add.Data})
+	if err != nil {
+		resp.Diagnostics.AddError(ParseAPIError(err, "create vault "+plan.Name.ValueString()))
+		return
+	}
+
+	// The generated master password is saved with a partially created vault
+	partialValues := map[string]attr.Value{}
+	if r.client.vaultPasswords() {
+		partialValues["master_password"] = types.StringValue(masterPassword)
+	}
 
 	// Send get request to get all fields
-	response_get, err = r.client.GetVault(response_add.Data)
+	response_get, err = r.client.GetVault(ctx, response_add.Data)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Creating Vault",
-			"Could not create Vault, unexpected error: "+err.Error(),
-		)
+		resp.Diagnostics.AddError(ParseAPIError(err, "read created vault "+response_add.Data))
+		savePartialState(ctx, req.Plan, resp, "vault", response_add.Data, partialValues)
 		return
 	}
 
 	// Convert response to state
-	newState, err = VaultResponseToModel(response_get)
+	newState, err = VaultResponseToModel(response_get, r.client)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error converting Vault API response to state.",
 			"Could not update state with API response, unexpected error: "+err.Error(),
 		)
+		savePartialState(ctx, req.Plan, resp, "vault", response_add.Data, partialValues)
 		return
 	}
 
+	// deletion_protection is not stored in Passwork
+	newState.DeletionProtection = plan.DeletionProtection
+	newState.ForceDestroy = plan.ForceDestroy
+
+	newState.Timeouts = plan.Timeouts
+
 	// Set refreshed state
 	diags = resp.State.Set(ctx, newState)
 	resp.Diagnostics.Append(diags...)
@@ -179,6 +222,9 @@ func (r *VaultResource) Create(ctx context.Context, req resource.CreateRequest,
 }
 
 func (r *VaultResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startSpan(ctx, "VaultResource.Read", "passwork_vault")
+	defer func() { endSpan(ctx, span, resp.Diagnostics) }()
+
 	var (
 		state    VaultResourceModel
 		newState VaultResourceModel
@@ -193,20 +239,37 @@ func (r *VaultResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
+	setSpanAttributes(span, map[string]string{"id": state.Id.ValueString(), "vault_id": state.Id.ValueString()})
+
+	// Bound the whole operation including retries and follow-up requests
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
 	// Get refreshed Vault value from Passwork
-	response, err = r.client.GetVault(state.Id.ValueString())
+	response, err = r.client.GetVault(ctx, state.Id.ValueString())
 
 	// Check for errors
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Reading Passwork Vault",
-			"Could not read Passwork Vault ID "+state.Id.ValueString()+": "+err.Error(),
-		)
+		// Remove resource from state, if it was deleted outside of Terraform
+		if removeNotFound(ctx, resp, "vault", state.Id.ValueString(), err) {
+			return
+		}
+		resp.Diagnostics.AddError(ParseAPIError(err, "read vault "+state.Id.ValueString()))
+		return
+	}
+
+	addVaultError(&resp.Diagnostics, path.Root("id"), r.client.checkVault(ctx, state.Id.ValueString()))
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	// Convert response to state
-	newState, err = VaultResponseToModel(response)
+	newState, err = VaultResponseToModel(response, r.client)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error converting Vault API response to state.",
@@ -215,6 +278,12 @@ func (r *VaultResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
+	// deletion_protection is not stored in Passwork and defaults to false for imported vaults
+	newState.DeletionProtection = localBoolState(state.DeletionProtection)
+	newState.ForceDestroy = localBoolState(state.ForceDestroy)
+
+	newState.Timeouts = state.Timeouts
+
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &newState)
 	resp.Diagnostics.Append(diags...)
@@ -224,6 +293,9 @@ func (r *VaultResource) Read(ctx context.Context, req resource.ReadRequest, resp
 }
 
 func (r *VaultResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startSpan(ctx, "VaultResource.Update", "passwork_vault")
+	defer func() { endSpan(ctx, span, resp.Diagnostics) }()
+
 	var (
 		plan         VaultResourceModel
 		newState     VaultResourceModel
@@ -240,30 +312,43 @@ func (r *VaultResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	setSpanAttributes(span, map[string]string{"id": plan.Id.ValueString(), "vault_id": plan.Id.ValueString()})
+
+	// Bound the whole operation including retries and follow-up requests
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	// Serialize writes within the vault, if enabled
+	unlock, err := r.client.lockVault(ctx, plan.Id.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(ParseAPIError(err, "lock vault "+plan.Id.ValueString()))
+		return
+	}
+	defer unlock()
+
 	// Create request from state
 	request.Name = plan.Name.ValueString()
 	// Send request
-	response, err = r.client.EditVault(plan.Id.ValueString(), request)
+	response, err = r.client.EditVault(ctx, plan.Id.ValueString(), request)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Updating Vault",
-			"Could not update Vault, unexpected error: "+err.Error(),
-		)
+		resp.Diagnostics.AddError(ParseAPIError(err, "update vault "+plan.Id.ValueString()))
 		return
 	}
 
 	// Send get request to get all fields
-	response_get, err = r.client.GetVault(response.Data)
+	response_get, err = r.client.GetVault(ctx, response.Data)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Creating Vault",
-			"Could not create Vault, unexpected error: "+err.Error(),
-		)
+		resp.Diagnostics.AddError(ParseAPIError(err, "read updated vault "+response.Data))
 		return
 	}
 
 	// Convert response to state
-	newState, err = VaultResponseToModel(response_get)
+	newState, err = VaultResponseToModel(response_get, r.client)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error converting Vault API response to state.",
@@ -272,6 +357,12 @@ func (r *VaultResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	// deletion_protection is not stored in Passwork
+	newState.DeletionProtection = plan.DeletionProtection
+	newState.ForceDestroy = plan.ForceDestroy
+
+	newState.Timeouts = plan.Timeouts
+
 	// Set refreshed state
 	diags = resp.State.Set(ctx, newState)
 	resp.Diagnostics.Append(diags...)
@@ -281,6 +372,9 @@ func (r *VaultResource) Update(ctx context.Context, req resource.UpdateRequest,
 }
 
 func (r *VaultResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startSpan(ctx, "VaultResource.Delete", "passwork_vault")
+	defer func() { endSpan(ctx, span, resp.Diagnostics) }()
+
 	var (
 		plan VaultResourceModel
 		err  error
@@ -293,17 +387,134 @@ func (r *VaultResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	// Send delete request
-	_, err = r.client.DeleteVault(plan.Id.ValueString())
+	setSpanAttributes(span, map[string]string{"id": plan.Id.ValueString(), "vault_id": plan.Id.ValueString()})
+
+	// Bound the whole operation including retries and follow-up requests
+	deleteTimeout, diags := plan.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	if plan.DeletionProtection.ValueBool() {
+		addDeletionProtectionError(&resp.Diagnostics, "Vault", plan.Id.ValueString())
+		return
+	}
+
+	// Serialize writes within the vault, if enabled
+	unlock, err := r.client.lockVault(ctx, plan.Id.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Deleting Vault",
-			"Could not delete Vault, unexpected error: "+err.Error(),
+		resp.Diagnostics.AddError(ParseAPIError(err, "lock vault "+plan.Id.ValueString()))
+		return
+	}
+	defer unlock()
+
+	// Check the vault for folders and passwords, the server might delete them silently
+	contents, err := r.client.listContents(ctx, plan.Id.ValueString(), "")
+	if isNotFound(err) {
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(ParseAPIError(err, "list the folders and passwords of vault "+plan.Id.ValueString()))
+		return
+	}
+
+	if !contents.empty() {
+		if !plan.ForceDestroy.ValueBool() {
+			resp.Diagnostics.AddError(
+				"Passwork Vault Is Not Empty",
+				"The vault "+plan.Id.ValueString()+" is not deleted, as it contains "+contents.summary()+":\n\n"+contents.describe()+"\n\n"+
+					"Delete the contents first, or set force_destroy = true in the configuration and apply the change to delete the vault with its contents.",
+			)
+			return
+		}
+
+		deleted, err := r.client.deleteContents(ctx, contents)
+		if err != nil {
+			summary, detail := ParseAPIError(err, "delete the contents of vault "+plan.Id.ValueString())
+			resp.Diagnostics.AddError(summary, detail+describeDeleted(deleted))
+			return
+		}
+		resp.Diagnostics.AddWarning(
+			"Deleted Passwork Vault Contents",
+			"The vault "+plan.Id.ValueString()+" was deleted with force_destroy including "+contents.summary()+":\n\n"+contents.describe(),
 		)
+	}
+
+	// Send delete request
+	_, err = r.client.DeleteVault(ctx, plan.Id.ValueString())
+	// A retried delete finds the vault already deleted by the first attempt
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError(ParseAPIError(err, "delete vault "+plan.Id.ValueString()))
 		return
 	}
 }
 
+func (r *VaultResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkReadOnly(r.client, req, resp, "passwork_vault")
+	checkPlannedVault(ctx, r.client, req, resp, "id")
+	checkPlannedVaultPassword(ctx, r.client, req, resp)
+	checkPlannedVaultEncryption(r.client, req, resp)
+
+	// New and renamed vaults are checked with their planned name
+	if r.client == nil || !r.client.vaultFilter.enabled() || resp.Diagnostics.HasError() {
+		return
+	}
+	if action := planAction(req); action != planActionCreate && action != planActionUpdate {
+		return
+	}
+
+	var plan VaultResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.Name.IsUnknown() {
+		return
+	}
+
+	addVaultError(&resp.Diagnostics, path.Root("name"), r.client.checkVaultName(plan.Id.ValueString(), plan.Name.ValueString()))
+}
+
+// checkPlannedVaultPassword rejects a configured master password, if the backend does not support
+// vault master passwords. Otherwise the master password is planned as null instead of generated.
+func checkPlannedVaultPassword(ctx context.Context, client *passworkClient, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if client == nil || client.vaultPasswords() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var configured types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("master_password"), &configured)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !configured.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("master_password"),
+			"Unsupported Passwork Configuration",
+			"The master_password of vaults is not supported by the provider for the Passwork 7 API. Remove master_password from the configuration.",
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("master_password"), types.StringNull())...)
+}
+
+// checkPlannedVaultEncryption rejects creating vaults with client-side encryption. Passwork then
+// stores a hash of the vault password, whose format is not known to the provider, and a vault
+// with a wrong hash could not be opened in Passwork. Existing vaults can be imported instead.
+func checkPlannedVaultEncryption(client *passworkClient, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if client == nil || !client.clientSideEncryption || planAction(req) != planActionCreate {
+		return
+	}
+
+	resp.Diagnostics.AddError(
+		"Unsupported Passwork Configuration",
+		"Creating vaults is not supported with client_side_encryption, as the provider cannot create the password hash, which Passwork stores for the vault. "+
+			"Create the vault in Passwork and import it with terraform import.",
+	)
+}
+
 func (r *VaultResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
@@ -322,18 +533,22 @@ func randomString(length int) string {
 	return string(result)
 }
 
-func VaultResponseToModel(response passwork.VaultResponse) (VaultResourceModel, error) {
+func VaultResponseToModel(response passwork.VaultResponse, client *passworkClient) (VaultResourceModel, error) {
 	var model VaultResourceModel
 
 	model.Id = types.StringValue(response.Data.Id)
 	model.Name = types.StringValue(response.Data.Name)
 	model.Access = types.StringValue(response.Data.Access)
 	model.Scope = types.StringValue(response.Data.Scope)
-	master_password, err := base64.StdEncoding.DecodeString(response.Data.VaultPasswordCrypted)
-	if err != nil {
-		return model, err
+	// The Passwork 7 API does not return the master password of the vault
+	model.MasterPassword = types.StringNull()
+	if client.vaultPasswords() {
+		master_password, err := client.decryptVaultPassword(response.Data.VaultPasswordCrypted)
+		if err != nil {
+			return model, err
+		}
+		model.MasterPassword = types.StringValue(master_password)
 	}
-	model.MasterPassword = types.StringValue(string(master_password))
 
 	if model.Scope.ValueString() == "user" {
 		model.IsPrivate = types.BoolValue(true)
@@ -23,6 +23,8 @@ func TestVaultResource(t *testing.T) {
 					resource.TestCheckResourceAttr("passwork_vault.test", "name", vaultName),
 					resource.TestCheckResourceAttr("passwork_vault.test", "is_private", "true"),
 					resource.TestCheckResourceAttrSet("passwork_vault.test", "id"),
+					resource.TestCheckResourceAttr("passwork_vault.test", "deletion_protection", "false"),
+					resource.TestCheckResourceAttr("passwork_vault.test", "force_destroy", "false"),
 					resource.TestCheckResourceAttrSet("passwork_vault.test", "master_password"),
 					resource.TestCheckResourceAttrSet("passwork_vault.test", "access"),
 					resource.TestCheckResourceAttrSet("passwork_vault.test", "scope"),
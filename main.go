@@ -43,7 +43,19 @@ func main() {
 		Debug:   debug,
 	}
 
-	err := providerserver.Serve(context.Background(), provider.New(version), opts)
+	ctx := context.Background()
+
+	// Export traces, if it is enabled with the OpenTelemetry environment variables
+	if err := provider.InitTracing(ctx, version); err != nil {
+		log.Printf("[WARN] Tracing is disabled: %s", err)
+	}
+
+	err := providerserver.Serve(ctx, provider.New(version), opts)
+
+	// End open Passwork sessions and export remaining traces once Terraform shuts down the provider
+	if err := provider.Shutdown(ctx); err != nil {
+		log.Printf("[WARN] Failed to export traces: %s", err)
+	}
 
 	if err != nil {
 		log.Fatal(err.Error())